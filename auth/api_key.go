@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//Declaring API key messages and errors
+var (
+	ErrAPIKeyScope = errors.New("Unknown API key scope")
+	invalidAPIKey  = "The API key is not valid, expired or was revoked"
+)
+
+//Authorization scheme of the API keys, "Authorization: ApiKey <key>"
+var apiKeyScheme = "ApiKey"
+
+//Key used to store the API key of the request in the gin context
+const apiKeyKey = "api_key"
+
+//CreateAPIKey generates an API key, the key is only returned here
+//because just its hash is stored
+func CreateAPIKey(in model.APIKeyInput, createdBy string) (model.APIKey, string, error) {
+	var key model.APIKey
+	for _, scope := range in.Scopes {
+		if scope != model.ScopeStats && scope != model.ScopeWrite {
+			return key, "", ErrAPIKeyScope
+		}
+	}
+	token, err := randomToken(32)
+	if err != nil {
+		return key, "", err
+	}
+	key.Name = in.Name
+	key.Hash = hashToken(token)
+	key.Scopes = strings.Join(in.Scopes, ",")
+	key.CreatedBy = createdBy
+	key.ExpiresAt = in.ExpiresAt
+	_, err = model.InsertAPIKey(&key)
+	return key, token, err
+}
+
+//validateAPIKey authenticates a request made with an API key
+func validateAPIKey(c *gin.Context, token string) {
+	key, err := model.UseAPIKey(hashToken(token))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": invalidAPIKey,
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		c.Abort()
+		return
+	}
+	c.Set(mailKey, "apikey:"+key.Name)
+	c.Set(apiKeyKey, key)
+	c.Next()
+}
+
+//This Middleware function,
+//it restricts a group of routes to the given roles
+//and to API keys with the given scope
+func RequireScope(scope string, roles ...int8) gin.HandlerFunc {
+	requireRole := RequireRole(roles...)
+	return func(c *gin.Context) {
+		key, ok := c.Get(apiKeyKey)
+		if !ok {
+			requireRole(c)
+			return
+		}
+		if key.(model.APIKey).HasScope(scope) {
+			c.Next()
+			return
+		}
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": forbiddenRole,
+		}
+		c.JSON(http.StatusForbidden, response)
+		c.Abort()
+	}
+}
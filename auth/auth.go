@@ -4,11 +4,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/fabulias/coimco_backend/model"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,19 +19,44 @@ var (
 	timeOutToken     = "Token expired"
 	invalidToken     = "The token is not valid"
 	headerNotFound   = "Authorization header not found"
+	invalidRole      = "Token role invalid"
+	invalidMail      = "Token mail invalid"
+	forbiddenRole    = "Your role doesn't have access to this resource"
+	revokedToken     = "The token was revoked"
+	inactiveAccount  = "The account is not active"
+)
+
+//Role of requests without account
+var noRole int8 = -1
+
+//Lifetime of the access tokens,
+//they are renewed with a refresh token
+var accessTokenTime = time.Minute * 15
+
+//Keys used to store the identity of the token in the gin context
+const (
+	mailKey = "mail"
+	roleKey = "role"
+	jtiKey  = "jti"
+	expKey  = "exp"
 )
 
 //This function return two things,
-//the first is a token with its respective time
+//the first is a token with its respective time and role
 //and second is a possible error in another case
-func CreateToken(mail string) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
+func CreateToken(mail string, role int8) (string, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	claims := jwt.MapClaims{}
 	claims["mail"] = mail
+	claims["role"] = role
+	claims["jti"] = jti
 	claims["iat"] = time.Now().Unix()
-	//Adding 30 days to expiration time
-	claims["exp"] = time.Now().Add(time.Hour * 24 * 30).Unix()
-	return token.SignedString([]byte(os.Getenv("MY_SIGN")))
+	//Access tokens are short-lived
+	claims["exp"] = time.Now().Add(accessTokenTime).Unix()
+	return keys.sign(claims)
 }
 
 //This Middleware function,
@@ -51,15 +76,13 @@ func ValidateToken() gin.HandlerFunc {
 			c.AbortWithStatus(http.StatusBadRequest)
 		} else {
 			tokenArray := strings.Split(headerAuth[0], " ")
-			tokenString := tokenArray[1]
-			token, err := jwt.Parse(tokenString,
-				func(token *jwt.Token) (interface{}, error) {
-					// Validating the algorithm used
-					if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-						return nil, unexpectedMethod
-					}
-					return []byte(os.Getenv("MY_SIGN")), nil
-				})
+			tokenString := tokenArray[len(tokenArray)-1]
+			//Machine integrations use API keys instead of tokens
+			if len(tokenArray) == 2 && tokenArray[0] == apiKeyScheme {
+				validateAPIKey(c, tokenString)
+				return
+			}
+			token, err := jwt.Parse(tokenString, keys.verify)
 			//If parsing ending with error
 			if err != nil {
 				response := gin.H{
@@ -82,6 +105,56 @@ func ValidateToken() gin.HandlerFunc {
 							"message": invalidTimeToken,
 						}
 						c.JSON(http.StatusUnauthorized, response)
+						c.Abort()
+						return
+					}
+					role, ok := claims[roleKey].(float64)
+					//Tokens without role can't be authorized
+					if !ok || int8(role) < model.SELLER || int8(role) > model.ADMIN {
+						response := gin.H{
+							"status":  "error",
+							"data":    nil,
+							"message": invalidRole,
+						}
+						c.JSON(http.StatusUnauthorized, response)
+						c.Abort()
+						return
+					}
+					mail, ok := claims[mailKey].(string)
+					//Tokens without mail don't identify an account
+					if !ok || mail == "" {
+						response := gin.H{
+							"status":  "error",
+							"data":    nil,
+							"message": invalidMail,
+						}
+						c.JSON(http.StatusUnauthorized, response)
+						c.Abort()
+						return
+					}
+					jti, ok := claims[jtiKey].(string)
+					//Tokens revoked by logout can't be used
+					if !ok || model.IsTokenRevoked(jti) {
+						response := gin.H{
+							"status":  "error",
+							"data":    nil,
+							"message": revokedToken,
+						}
+						c.JSON(http.StatusUnauthorized, response)
+						c.Abort()
+						return
+					}
+					//Deactivated accounts are cut off immediately
+					account, err := model.GetAccount(mail)
+					if err != nil || !account.Active {
+						response := gin.H{
+							"status":  "error",
+							"data":    nil,
+							"message": inactiveAccount,
+						}
+						c.JSON(http.StatusUnauthorized, response)
+						c.Abort()
+						return
 					}
 					//Token time is expired
 					if exp < time.Now().Unix() {
@@ -94,6 +167,11 @@ func ValidateToken() gin.HandlerFunc {
 						c.Abort()
 					} else {
 						// Good case! :)
+						c.Set(mailKey, mail)
+						//The role is the current one, not the one of the token
+						c.Set(roleKey, account.Role)
+						c.Set(jtiKey, jti)
+						c.Set(expKey, exp)
 						c.Next()
 					}
 				} else {
@@ -104,9 +182,68 @@ func ValidateToken() gin.HandlerFunc {
 						"message": invalidToken,
 					}
 					c.JSON(http.StatusUnauthorized, response)
+					c.Abort()
 				}
 			}
 		}
 
 	}
 }
+
+//Mail returns the mail of the account that made the request,
+//it must be called after ValidateToken
+func Mail(c *gin.Context) string {
+	mail, ok := c.Get(mailKey)
+	if !ok {
+		return ""
+	}
+	return mail.(string)
+}
+
+//Role returns the role of the account that made the request,
+//it must be called after ValidateToken.
+//Requests made with API keys don't have a role
+func Role(c *gin.Context) int8 {
+	role, ok := c.Get(roleKey)
+	if !ok {
+		return noRole
+	}
+	return role.(int8)
+}
+
+//RevokeToken revokes the access token used in the request,
+//it must be called after ValidateToken
+func RevokeToken(c *gin.Context) error {
+	jti, ok := c.Get(jtiKey)
+	if !ok {
+		return nil
+	}
+	exp := c.MustGet(expKey).(int64)
+	in := model.RevokedToken{
+		ID:        jti.(string),
+		ExpiresAt: time.Unix(exp, 0),
+	}
+	_, err := model.InsertRevokedToken(&in)
+	return err
+}
+
+//This Middleware function,
+//it restricts a group of routes to the given roles
+func RequireRole(roles ...int8) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := Role(c)
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": forbiddenRole,
+		}
+		c.JSON(http.StatusForbidden, response)
+		c.Abort()
+	}
+}
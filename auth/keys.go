@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+//Declaring key set errors
+var (
+	unknownKey     = errors.New("Unknown signing key")
+	invalidKeyPair = errors.New("Keys must be written as kid:value")
+)
+
+//Key id of tokens signed before key ids, they're verified with MY_SIGN
+var defaultKeyID = "default"
+
+//signingKey is a key able to verify tokens,
+//private is nil if the key can't sign
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+//keySet contains the key used to sign new tokens
+//and every key accepted to verify tokens
+type keySet struct {
+	current *signingKey
+	keys    map[string]*signingKey
+}
+
+//Keys are loaded once when the server starts
+var keys = loadKeySet()
+
+//loadKeySet reads the keys from environment variables:
+//
+//  JWT_ALG          HS256 (default), RS256 or ES256, algorithm of new tokens
+//  JWT_KEY_ID       kid of the key that signs new tokens
+//  JWT_KEYS         HMAC secrets as kid:secret,kid:secret
+//  JWT_PRIVATE_KEY  PEM file of the private key when JWT_ALG isn't HS256
+//  JWT_PUBLIC_KEYS  PEM files of old public keys as kid:file,kid:file
+//  MY_SIGN          HMAC secret of tokens without kid
+//
+//To rotate a key, add the new key with a new kid, point JWT_KEY_ID to it
+//and restart. Keep the old key until the tokens signed with it expire
+//(the access token lifetime), then remove it.
+func loadKeySet() *keySet {
+	set, err := newKeySet(os.Getenv("JWT_ALG"), os.Getenv("JWT_KEY_ID"))
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+	return set
+}
+
+//newKeySet builds the key set, alg and kid select the signing key
+func newKeySet(alg, kid string) (*keySet, error) {
+	set := &keySet{keys: make(map[string]*signingKey)}
+	if secret := os.Getenv("MY_SIGN"); secret != "" {
+		set.add(&signingKey{defaultKeyID, jwt.SigningMethodHS256,
+			[]byte(secret), []byte(secret)})
+	}
+	pairs, err := splitPairs(os.Getenv("JWT_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range pairs {
+		set.add(&signingKey{pair[0], jwt.SigningMethodHS256,
+			[]byte(pair[1]), []byte(pair[1])})
+	}
+	pairs, err = splitPairs(os.Getenv("JWT_PUBLIC_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range pairs {
+		key, err := loadPublicKey(pair[0], pair[1])
+		if err != nil {
+			return nil, err
+		}
+		set.add(key)
+	}
+	switch alg {
+	case "", "HS256":
+		if kid == "" {
+			kid = defaultKeyID
+		}
+		//Tokens are never signed with an empty secret
+		if kid == defaultKeyID && set.keys[kid] == nil {
+			return nil, errors.New("MY_SIGN or JWT_KEYS is required with HS256")
+		}
+	case "RS256", "ES256":
+		if kid == "" {
+			return nil, errors.New("JWT_KEY_ID is required with " + alg)
+		}
+		key, err := loadPrivateKey(kid, alg, os.Getenv("JWT_PRIVATE_KEY"))
+		if err != nil {
+			return nil, err
+		}
+		set.add(key)
+	default:
+		return nil, errors.New("Unsupported JWT_ALG " + alg)
+	}
+	set.current = set.keys[kid]
+	if set.current == nil || set.current.private == nil {
+		return nil, errors.New("There is no signing key " + kid)
+	}
+	return set, nil
+}
+
+//add registers a verification key
+func (set *keySet) add(key *signingKey) {
+	set.keys[key.id] = key
+}
+
+//sign signs a token with the current key
+func (set *keySet) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(set.current.method, claims)
+	token.Header["kid"] = set.current.id
+	return token.SignedString(set.current.private)
+}
+
+//verify is the jwt.Keyfunc of the key set
+func (set *keySet) verify(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		kid = defaultKeyID
+	}
+	key, ok := set.keys[kid]
+	if !ok {
+		return nil, unknownKey
+	}
+	// Validating the algorithm used
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, unexpectedMethod
+	}
+	return key.public, nil
+}
+
+//splitPairs parses a list written as kid:value,kid:value
+func splitPairs(list string) ([][2]string, error) {
+	var pairs [][2]string
+	if list == "" {
+		return pairs, nil
+	}
+	for _, item := range strings.Split(list, ",") {
+		pair := strings.SplitN(strings.TrimSpace(item), ":", 2)
+		if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+			return nil, invalidKeyPair
+		}
+		pairs = append(pairs, [2]string{pair[0], pair[1]})
+	}
+	return pairs, nil
+}
+
+//loadPrivateKey reads a RSA or EC private key from a PEM file
+func loadPrivateKey(kid, alg, path string) (*signingKey, error) {
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if alg == "RS256" {
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+		return &signingKey{kid, jwt.SigningMethodRS256,
+			private, &private.PublicKey}, nil
+	}
+	private, err := jwt.ParseECPrivateKeyFromPEM(pem)
+	if err != nil {
+		return nil, err
+	}
+	return &signingKey{kid, jwt.SigningMethodES256,
+		private, &private.PublicKey}, nil
+}
+
+//loadPublicKey reads a RSA or EC public key from a PEM file
+func loadPublicKey(kid, path string) (*signingKey, error) {
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if public, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+		return &signingKey{kid, jwt.SigningMethodRS256, nil, public}, nil
+	}
+	public, err := jwt.ParseECPublicKeyFromPEM(pem)
+	if err != nil {
+		return nil, err
+	}
+	return &signingKey{kid, jwt.SigningMethodES256, nil, public}, nil
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/fabulias/coimco_backend/model"
+)
+
+//Declaring refresh token errors
+var (
+	ErrRefreshToken    = errors.New("The refresh token is not valid")
+	ErrRefreshExpired  = errors.New("The refresh token expired")
+	ErrRefreshReused   = errors.New("The refresh token was already used")
+	ErrInactiveAccount = errors.New("The account is not active")
+)
+
+//Lifetime of the refresh tokens
+var refreshTokenTime = time.Hour * 24 * 30
+
+//randomToken returns a random hexadecimal string of n bytes
+func randomToken(n int) (string, error) {
+	bytes := make([]byte, n)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+//hashToken returns the hash stored in database for a refresh token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+//CreateRefreshToken generates a refresh token to an account
+//and stores its hash in database
+func CreateRefreshToken(mail string) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	in := model.RefreshToken{
+		ID:        hashToken(token),
+		UserID:    mail,
+		ExpiresAt: time.Now().Add(refreshTokenTime),
+	}
+	_, err = model.InsertRefreshToken(&in)
+	return token, err
+}
+
+//RotateRefreshToken revokes a refresh token and returns the account,
+//a new access token and a new refresh token.
+//If a revoked token is used again, it could be stolen,
+//so every refresh token of the account is revoked
+func RotateRefreshToken(token string) (model.UserAcc, string, string, error) {
+	var acc model.UserAcc
+	stored, err := model.GetRefreshToken(hashToken(token))
+	if err != nil {
+		return acc, "", "", ErrRefreshToken
+	}
+	if stored.RevokedAt != nil {
+		model.RevokeRefreshTokens(stored.UserID)
+		return acc, "", "", ErrRefreshReused
+	}
+	if stored.ExpiresAt.Before(time.Now()) {
+		return acc, "", "", ErrRefreshExpired
+	}
+	revoked, err := model.RevokeRefreshToken(stored.ID)
+	if err != nil {
+		return acc, "", "", err
+	}
+	//Another request rotated this token first
+	if !revoked {
+		model.RevokeRefreshTokens(stored.UserID)
+		return acc, "", "", ErrRefreshReused
+	}
+	acc, err = model.GetAccount(stored.UserID)
+	if err != nil || !acc.Active {
+		return acc, "", "", ErrInactiveAccount
+	}
+	access, err := CreateToken(acc.Mail, acc.Role)
+	if err != nil {
+		return acc, "", "", err
+	}
+	refresh, err := CreateRefreshToken(acc.Mail)
+	return acc, access, refresh, err
+}
+
+//RevokeRefreshToken revokes a refresh token of an account
+func RevokeRefreshToken(mail, token string) error {
+	stored, err := model.GetRefreshToken(hashToken(token))
+	if err != nil || stored.UserID != mail {
+		return ErrRefreshToken
+	}
+	_, err = model.RevokeRefreshToken(stored.ID)
+	return err
+}
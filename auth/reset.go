@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/fabulias/coimco_backend/hash"
+	"github.com/fabulias/coimco_backend/model"
+)
+
+//ErrResetToken is returned when a reset token can't be used
+var ErrResetToken = errors.New("The reset token is not valid or expired")
+
+//Lifetime of the password reset tokens
+var resetTokenTime = time.Hour
+
+//CreateResetToken generates a single-use token to reset
+//the password of an active account
+func CreateResetToken(mail string) (string, error) {
+	if !model.IsActiveAccount(mail) {
+		return "", ErrInactiveAccount
+	}
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	in := model.ResetToken{
+		ID:        hashToken(token),
+		UserID:    mail,
+		ExpiresAt: time.Now().Add(resetTokenTime),
+	}
+	_, err = model.InsertResetToken(&in)
+	return token, err
+}
+
+//ResetPassword uses a reset token to change the password of its account
+func ResetPassword(token, pass string) (model.UserAcc, error) {
+	stored, err := model.UseResetToken(hashToken(token))
+	if err != nil {
+		return model.UserAcc{}, ErrResetToken
+	}
+	hash_pass, err := hash.HashPassword(pass)
+	if err != nil {
+		return model.UserAcc{}, err
+	}
+	return model.SetPassAccount(stored.UserID, hash_pass)
+}
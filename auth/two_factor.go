@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/fabulias/coimco_backend/totp"
+)
+
+//Declaring second factor errors
+var (
+	ErrChallenge         = errors.New("The second factor challenge is not valid or expired")
+	ErrSecondFactor      = errors.New("The second factor code is not valid")
+	ErrTOTPNotPending    = errors.New("There is no second factor enrollment pending")
+	ErrTOTPRequired      = errors.New("The second factor is required for your role")
+	ErrTOTPAlreadyActive = errors.New("The second factor is already enabled")
+)
+
+//Lifetime of the challenges between password and second factor
+var challengeTime = time.Minute * 5
+
+//Purpose claim of the challenge tokens, they aren't access tokens
+var challengePurpose = "totp"
+
+//Number of recovery codes generated on enrollment
+var recoveryCodes = 10
+
+//Roles that must use a second factor, from TOTP_REQUIRED_ROLES (e.g. "1,2")
+var totpRequiredRoles = loadRequiredRoles(os.Getenv("TOTP_REQUIRED_ROLES"))
+
+//loadRequiredRoles parses a list of roles
+func loadRequiredRoles(list string) map[int8]bool {
+	roles := make(map[int8]bool)
+	for _, item := range strings.Split(list, ",") {
+		role, err := strconv.ParseInt(strings.TrimSpace(item), 10, 8)
+		if err == nil {
+			roles[int8(role)] = true
+		}
+	}
+	return roles
+}
+
+//TOTPRequired returns true if the role must use a second factor
+func TOTPRequired(role int8) bool {
+	return totpRequiredRoles[role]
+}
+
+//SecondFactorPending returns true if the account must verify
+//a second factor, or enroll one, before getting a token
+func SecondFactorPending(acc model.UserAcc) bool {
+	return acc.TOTPEnabled || TOTPRequired(acc.Role)
+}
+
+//CreateChallenge returns a short-lived token that proves the password
+//of an account was verified
+func CreateChallenge(mail string) (string, error) {
+	claims := jwt.MapClaims{}
+	claims["mail"] = mail
+	claims["purpose"] = challengePurpose
+	claims["iat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(challengeTime).Unix()
+	return keys.sign(claims)
+}
+
+//ChallengeAccount returns the active account of a challenge
+func ChallengeAccount(challenge string) (model.UserAcc, error) {
+	token, err := jwt.Parse(challenge, keys.verify)
+	if err != nil || !token.Valid {
+		return model.UserAcc{}, ErrChallenge
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	mail, _ := claims["mail"].(string)
+	if claims["purpose"] != challengePurpose || mail == "" {
+		return model.UserAcc{}, ErrChallenge
+	}
+	acc, err := model.GetAccount(mail)
+	if err != nil || !acc.Active {
+		return acc, ErrInactiveAccount
+	}
+	return acc, nil
+}
+
+//EnrollTOTP generates a pending second factor secret to an account,
+//and returns it with its provisioning URI
+func EnrollTOTP(acc model.UserAcc) (string, string, error) {
+	if acc.TOTPEnabled {
+		return "", "", ErrTOTPAlreadyActive
+	}
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return "", "", err
+	}
+	err = model.SetTOTPSecret(acc.Mail, secret)
+	return secret, totp.URI(acc.Mail, secret), err
+}
+
+//EnrollChallenge is EnrollTOTP for accounts that must enroll while signing in
+func EnrollChallenge(challenge string) (string, string, error) {
+	acc, err := ChallengeAccount(challenge)
+	if err != nil {
+		return "", "", err
+	}
+	return EnrollTOTP(acc)
+}
+
+//ConfirmTOTP enables the pending second factor if the code is valid,
+//and returns the new recovery codes
+func ConfirmTOTP(acc model.UserAcc, code string) ([]string, error) {
+	if acc.TOTPEnabled {
+		return nil, ErrTOTPAlreadyActive
+	}
+	if acc.TOTPSecret == "" {
+		return nil, ErrTOTPNotPending
+	}
+	if err := useTOTP(acc, code); err != nil {
+		return nil, err
+	}
+	codes := make([]string, recoveryCodes)
+	hashes := make([]string, recoveryCodes)
+	for i := range codes {
+		code, err := randomToken(5)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashToken(code)
+	}
+	return codes, model.EnableTOTP(acc.Mail, hashes)
+}
+
+//DisableTOTP removes the second factor of an account after verifying a code
+func DisableTOTP(acc model.UserAcc, code string) error {
+	if TOTPRequired(acc.Role) {
+		return ErrTOTPRequired
+	}
+	if err := verifySecondFactor(acc, code); err != nil {
+		return err
+	}
+	return model.DisableTOTP(acc.Mail)
+}
+
+//VerifyChallenge finishes a sign in with a TOTP or recovery code.
+//Accounts enrolling while signing in also get their recovery codes
+func VerifyChallenge(acc model.UserAcc, code string) ([]string, error) {
+	if !acc.TOTPEnabled {
+		return ConfirmTOTP(acc, code)
+	}
+	return nil, verifySecondFactor(acc, code)
+}
+
+//verifySecondFactor accepts a TOTP code or a recovery code
+func verifySecondFactor(acc model.UserAcc, code string) error {
+	if !acc.TOTPEnabled {
+		return ErrTOTPNotPending
+	}
+	if useTOTP(acc, code) == nil {
+		return nil
+	}
+	used, err := model.UseRecoveryCode(acc.Mail, hashToken(strings.ToLower(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrSecondFactor
+	}
+	return nil
+}
+
+//useTOTP checks a TOTP code, each code can be used once
+func useTOTP(acc model.UserAcc, code string) error {
+	step, ok := totp.Check(acc.TOTPSecret, code, time.Now())
+	if !ok {
+		return ErrSecondFactor
+	}
+	used, err := model.UseTOTPStep(acc.Mail, step)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrSecondFactor
+	}
+	return nil
+}
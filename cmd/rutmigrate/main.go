@@ -0,0 +1,168 @@
+//Command rutmigrate reports the RUTs of customers, providers and accounts
+//that aren't valid or aren't normalized, and normalizes them with -apply.
+//Customers and providers whose normalized RUT already exists are merged
+//into the existing one, moving their sales, back-orders, purchases and tags.
+//
+//	DATABASE_URL=... rutmigrate [-apply]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/fabulias/coimco_backend/rut"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+)
+
+//reference is a column that references the RUT of an agent,
+//Key is the other column of its primary key, if any
+type reference struct {
+	Table  string
+	Column string
+	Key    string
+}
+
+//agentTable is a table keyed by RUT and the columns that reference it
+type agentTable struct {
+	Name       string
+	References []reference
+}
+
+var agentTables = []agentTable{
+	{"customer", []reference{
+		{"sale", "customer_id", ""},
+		{"back_order", "customer_id", ""},
+		{"tag_customer", "customer_id", "tag_id"},
+	}},
+	{"provider", []reference{
+		{"purchase", "provider_id", ""},
+	}},
+}
+
+//Columns of the agent tables, see model.Agent
+const agentColumns = "name, mail, phone, created_at, updated_at, deleted_at"
+
+func main() {
+	apply := flag.Bool("apply", false, "normalize the RUTs, otherwise they're only reported")
+	flag.Parse()
+
+	db, err := gorm.Open("postgres", os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer db.Close()
+
+	invalid, changed := 0, 0
+	for _, table := range agentTables {
+		var ruts []string
+		err = db.Table(table.Name).Pluck("rut", &ruts).Error
+		if err != nil {
+			log.Fatalln(err)
+		}
+		for _, old := range ruts {
+			normal, err := rut.Normalize(old)
+			if err != nil {
+				fmt.Printf("%s %q invalid\n", table.Name, old)
+				invalid++
+				continue
+			}
+			if normal == old {
+				continue
+			}
+			changed++
+			merged, err := moveAgent(db, table, old, normal, *apply)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			if merged {
+				fmt.Printf("%s %q -> %q (merged)\n", table.Name, old, normal)
+			} else {
+				fmt.Printf("%s %q -> %q\n", table.Name, old, normal)
+			}
+		}
+	}
+
+	var accounts []struct {
+		Mail string
+		Rut  string
+	}
+	err = db.Table("user_acc").Select("mail, rut").Scan(&accounts).Error
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, account := range accounts {
+		normal, err := rut.Normalize(account.Rut)
+		if err != nil {
+			fmt.Printf("user_acc %s %q invalid\n", account.Mail, account.Rut)
+			invalid++
+			continue
+		}
+		if normal == account.Rut {
+			continue
+		}
+		changed++
+		fmt.Printf("user_acc %s %q -> %q\n", account.Mail, account.Rut, normal)
+		if *apply {
+			err = db.Exec("UPDATE user_acc SET rut = ? WHERE mail = ?",
+				normal, account.Mail).Error
+			if err != nil {
+				log.Fatalln(err)
+			}
+		}
+	}
+
+	if *apply {
+		fmt.Printf("%d normalized, %d invalid\n", changed, invalid)
+	} else {
+		fmt.Printf("%d to normalize, %d invalid, run with -apply to normalize\n",
+			changed, invalid)
+	}
+}
+
+//moveAgent moves the agent and its references from the old RUT to the new
+//one in a transaction, it return true if the new RUT already existed.
+//Nothing is written unless apply is true
+func moveAgent(db *gorm.DB, table agentTable, old, normal string, apply bool) (bool, error) {
+	var count int
+	err := db.Table(table.Name).Where("rut = ?", normal).Count(&count).Error
+	if err != nil || !apply {
+		return count > 0, err
+	}
+	tx := db.Begin()
+	if count == 0 {
+		err = tx.Exec("INSERT INTO "+table.Name+" (rut, "+agentColumns+") "+
+			"SELECT ?, "+agentColumns+" FROM "+table.Name+" WHERE rut = ?",
+			normal, old).Error
+		if err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+	for _, ref := range table.References {
+		if ref.Key != "" {
+			//Rows already on the new RUT would be duplicated
+			err = tx.Exec("DELETE FROM "+ref.Table+" WHERE "+ref.Column+" = ? AND "+
+				ref.Key+" IN (SELECT "+ref.Key+" FROM "+ref.Table+" WHERE "+ref.Column+" = ?)",
+				old, normal).Error
+			if err != nil {
+				tx.Rollback()
+				return false, err
+			}
+		}
+		err = tx.Exec("UPDATE "+ref.Table+" SET "+ref.Column+" = ? WHERE "+ref.Column+" = ?",
+			normal, old).Error
+		if err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+	err = tx.Exec("DELETE FROM "+table.Name+" WHERE rut = ?", old).Error
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	return count > 0, tx.Commit().Error
+}
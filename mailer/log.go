@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+//LogMailer writes mails to a file, or to the log if Path is empty.
+//It's intended for local development
+type LogMailer struct {
+	Path string
+	mu   sync.Mutex
+}
+
+//Send writes the mail
+func (m *LogMailer) Send(to, subject, body string) error {
+	msg := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", to, subject, body)
+	if m.Path == "" {
+		log.Println("Mail ->", msg)
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	file, err := os.OpenFile(m.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintf(file, "Date: %s\n%s\n", time.Now().Format(time.RFC1123Z), msg)
+	return err
+}
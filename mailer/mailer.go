@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"log"
+	"os"
+)
+
+//Mailer sends mails to the users of the application
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+//New returns the mailer configured in MAILER environment variable,
+//"smtp" uses SMTP_* variables, in another case mails are logged
+func New() Mailer {
+	if os.Getenv("MAILER") == "smtp" {
+		log.Println("Initialize SMTP mailer")
+		return &SMTPMailer{
+			Host: os.Getenv("SMTP_HOST"),
+			Port: os.Getenv("SMTP_PORT"),
+			User: os.Getenv("SMTP_USER"),
+			Pass: os.Getenv("SMTP_PASS"),
+			From: os.Getenv("SMTP_FROM"),
+		}
+	}
+	log.Println("Initialize log mailer")
+	return &LogMailer{Path: os.Getenv("MAILER_FILE")}
+}
@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+)
+
+//SMTPMailer sends mails through a SMTP server
+type SMTPMailer struct {
+	Host string
+	Port string
+	User string
+	Pass string
+	From string
+}
+
+//Send sends a plain text mail
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		m.From, to, subject, body)
+	var auth smtp.Auth
+	if m.User != "" {
+		auth = smtp.PlainAuth("", m.User, m.Pass, m.Host)
+	}
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.From,
+		[]string{to}, []byte(msg))
+}
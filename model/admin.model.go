@@ -2,6 +2,7 @@ package model
 
 //This function allow insert account
 func InsertAccount(in *UserAcc) (*UserAcc, error) {
+	in.Rut = normalRut(in.Rut)
 	err = dbmap.Create(in).Error
 	return in, err
 }
@@ -13,3 +14,91 @@ func GetAccount(mail string) (UserAcc, error) {
 	err := dbmap.Where("mail=?", mail).First(&account).Error
 	return account, err
 }
+
+//GetAccounts return accounts filtered by role and state,
+//nil filters are ignored
+func GetAccounts(role *int8, active *bool) ([]UserAcc, error) {
+	var accounts []UserAcc
+	query := dbmap.Order("mail")
+	if role != nil {
+		query = query.Where("role = ?", *role)
+	}
+	if active != nil {
+		query = query.Where("active = ?", *active)
+	}
+	err := query.Find(&accounts).Error
+	checkErr(err, selectFailed)
+	return accounts, err
+}
+
+//UpdateAccount updates the personal data of an account
+func UpdateAccount(mail string, in AccountUpdate) (UserAcc, error) {
+	account, err := GetAccount(mail)
+	if err != nil {
+		return account, err
+	}
+	err = dbmap.Model(&account).Updates(map[string]interface{}{
+		"name":     in.Name,
+		"lastname": in.Lastname,
+		"rut":      normalRut(in.Rut),
+	}).Error
+	return account, err
+}
+
+//SetRoleAccount changes the role of an account,
+//it applies to the next requests and the account must sign in again
+func SetRoleAccount(mail string, role int8) (UserAcc, error) {
+	account, err := GetAccount(mail)
+	if err != nil {
+		return account, err
+	}
+	err = dbmap.Model(&account).Update("role", role).Error
+	if err != nil {
+		return account, err
+	}
+	return account, RevokeRefreshTokens(mail)
+}
+
+//SetPassAccount changes the password hash of an account
+//and revokes its refresh tokens
+func SetPassAccount(mail, hash string) (UserAcc, error) {
+	account, err := GetAccount(mail)
+	if err != nil {
+		return account, err
+	}
+	err = dbmap.Model(&account).Update("pass", hash).Error
+	if err != nil {
+		return account, err
+	}
+	return account, RevokeRefreshTokens(mail)
+}
+
+//DeleteAccount soft deletes and deactivates an account
+func DeleteAccount(mail string) (UserAcc, error) {
+	account, err := SetActiveAccount(mail, false)
+	if err != nil {
+		return account, err
+	}
+	err = dbmap.Delete(&account).Error
+	return account, err
+}
+
+//IsActiveAccount return true if the account exists and it's active
+func IsActiveAccount(mail string) bool {
+	account, err := GetAccount(mail)
+	return err == nil && account.Active
+}
+
+//SetActiveAccount activates or deactivates an account,
+//deactivated accounts lose their refresh tokens
+func SetActiveAccount(mail string, active bool) (UserAcc, error) {
+	account, err := GetAccount(mail)
+	if err != nil {
+		return account, err
+	}
+	err = dbmap.Model(&account).Update("active", active).Error
+	if err != nil || active {
+		return account, err
+	}
+	return account, RevokeRefreshTokens(mail)
+}
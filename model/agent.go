@@ -4,12 +4,18 @@ import "time"
 
 //Represents base of the clients and providers in the application
 type Agent struct {
-	Rut   string `json:"rut" binding:"required" gorm:"primary_key;type:varchar(20)"`
+	Rut   string `json:"rut" binding:"required,rut" gorm:"primary_key;type:varchar(20)"`
 	Name  string `json:"name" binding:"required"`
-	Mail  string `json:"mail" binding:"required"`
+	Mail  string `json:"mail" binding:"required,email"`
 	Phone string `json:"phone"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
 }
+
+//AgentFields are the columns of clients and providers lists
+var AgentFields = ListFields{
+	Key:     "rut",
+	Columns: []string{"rut", "name", "mail", "phone", "created_at", "updated_at"},
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+//Scopes of the API keys
+var (
+	ScopeStats = "stats"
+	ScopeWrite = "write"
+)
+
+//APIKey represents the identity of a machine integration,
+//only the hash of the key is stored
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primary_key"`
+	Name       string     `json:"name"`
+	Hash       string     `json:"-" gorm:"type:varchar(64);unique_index"`
+	Scopes     string     `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+//Represent API key input
+type APIKeyInput struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+//InsertAPIKey insert an API key in database
+func InsertAPIKey(in *APIKey) (*APIKey, error) {
+	err = dbmap.Create(in).Error
+	return in, err
+}
+
+//GetAPIKeys return all API keys
+func GetAPIKeys() ([]APIKey, error) {
+	var keys []APIKey
+	err := dbmap.Order("id").Find(&keys).Error
+	checkErr(err, selectFailed)
+	return keys, err
+}
+
+//GetAPIKey return an API key with that id
+func GetAPIKey(id uint) (APIKey, error) {
+	var key APIKey
+	err := dbmap.First(&key, id).Error
+	return key, err
+}
+
+//UseAPIKey return the API key with that hash if it's usable,
+//and updates its last used time
+func UseAPIKey(hash string) (APIKey, error) {
+	var key APIKey
+	now := time.Now()
+	err := dbmap.Where("hash = ? AND revoked_at IS NULL AND "+
+		"(expires_at IS NULL OR expires_at > ?)", hash, now).First(&key).Error
+	if err != nil {
+		return key, err
+	}
+	err = dbmap.Model(&key).UpdateColumn("last_used_at", now).Error
+	checkErr(err, updateFailed)
+	return key, nil
+}
+
+//RevokeAPIKey revokes an API key with its id
+func RevokeAPIKey(id uint) (APIKey, error) {
+	var key APIKey
+	err := dbmap.First(&key, id).Error
+	if err != nil {
+		return key, err
+	}
+	err = dbmap.Model(&key).UpdateColumn("revoked_at", time.Now()).Error
+	return key, err
+}
+
+//HasScope return true if the API key has that scope
+func (key APIKey) HasScope(scope string) bool {
+	for _, s := range strings.Split(key.Scopes, ",") {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//AuditLog represents a write operation made through the API,
+//Before and After are JSON documents of the entity
+type AuditLog struct {
+	ID        uint   `json:"id" gorm:"primary_key"`
+	Actor     string `json:"actor" gorm:"index"`
+	Action    string `json:"action"`
+	Entity    string `json:"entity" gorm:"index"`
+	EntityKey string `json:"entity_key"`
+	Path      string `json:"path"`
+	Before    string `json:"before" gorm:"type:text"`
+	After     string `json:"after" gorm:"type:text"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+//MarshalJSON serializes Before and After as JSON instead of strings
+func (a AuditLog) MarshalJSON() ([]byte, error) {
+	type auditLog AuditLog
+	return json.Marshal(struct {
+		auditLog
+		Before json.RawMessage `json:"before"`
+		After  json.RawMessage `json:"after"`
+	}{auditLog(a), rawJSON(a.Before), rawJSON(a.After)})
+}
+
+//rawJSON returns a valid JSON document, null if it's empty or invalid
+func rawJSON(doc string) json.RawMessage {
+	if doc == "" || !json.Valid([]byte(doc)) {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(doc)
+}
+
+//Represent audit log query filters
+type AuditFilter struct {
+	Actor  string
+	Entity string
+	Start  *time.Time
+	End    *time.Time
+	Limit  int
+}
@@ -0,0 +1,29 @@
+package model
+
+//InsertAuditLog insert an audit log entry in database
+func InsertAuditLog(in *AuditLog) (*AuditLog, error) {
+	err = dbmap.Create(in).Error
+	return in, err
+}
+
+//GetAuditLogs return the last audit log entries
+//that match the filters, empty filters are ignored
+func GetAuditLogs(in AuditFilter) ([]AuditLog, error) {
+	var logs []AuditLog
+	query := dbmap.Order("created_at DESC").Limit(in.Limit)
+	if in.Actor != "" {
+		query = query.Where("actor = ?", in.Actor)
+	}
+	if in.Entity != "" {
+		query = query.Where("entity = ?", in.Entity)
+	}
+	if in.Start != nil {
+		query = query.Where("created_at >= ?", *in.Start)
+	}
+	if in.End != nil {
+		query = query.Where("created_at <= ?", *in.End)
+	}
+	err := query.Find(&logs).Error
+	checkErr(err, selectFailed)
+	return logs, err
+}
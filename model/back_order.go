@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+//BackOrder represents the quantity of a sale line that wasn't in stock,
+//Pending is shipped when purchases of the product are received
+type BackOrder struct {
+	ID          uint       `json:"id" gorm:"primary_key"`
+	SaleID      uint       `json:"sale_id" gorm:"index"`
+	ProductID   uint       `json:"product_id" gorm:"index"`
+	WarehouseID uint       `json:"warehouse_id"`
+	CustomerID  string     `json:"customer_id" gorm:"index"`
+	UserID      string     `json:"user_id"`
+	Quantity    uint       `json:"quantity"`
+	Pending     uint       `json:"pending"`
+	FulfilledAt *time.Time `json:"fulfilled_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//BackOrderFields are the columns of back-orders lists,
+//pending ones are those with 'min=1'
+var BackOrderFields = ListFields{
+	Key:     "id",
+	Date:    "created_at",
+	Amount:  "pending",
+	Columns: []string{"id", "sale_id", "product_id", "warehouse_id", "customer_id", "user_id", "pending", "created_at"},
+}
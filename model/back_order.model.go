@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+//GetBackOrders return a page of back-orders
+func GetBackOrders(in ListQuery) ([]BackOrder, Page, error) {
+	normalRutFilter(in, "customer_id")
+	var backOrders []BackOrder
+	page, err := list(&backOrders, in, BackOrderFields)
+	return backOrders, page, err
+}
+
+//getSaleBackOrders return the back-orders of a sale
+func getSaleBackOrders(sale_id uint) ([]BackOrder, error) {
+	var backOrders []BackOrder
+	err := dbmap.Where("sale_id = ?", sale_id).Order("product_id").Find(&backOrders).Error
+	checkErr(err, selectFailed)
+	return backOrders, err
+}
+
+//fulfillBackOrders ships the pending back-orders of a product in a
+//warehouse, oldest first, while there is stock on hand
+func fulfillBackOrders(tx *gorm.DB, product_id, warehouse_id uint) error {
+	onHand, err := lockStock(tx, product_id, warehouse_id)
+	if err != nil || onHand <= 0 {
+		return err
+	}
+	var backOrders []BackOrder
+	err = tx.Where("product_id = ? AND warehouse_id = ? AND pending > 0", product_id, warehouse_id).
+		Order("created_at, id").Find(&backOrders).Error
+	if err != nil {
+		return err
+	}
+	for _, backOrder := range backOrders {
+		if onHand <= 0 {
+			break
+		}
+		shipped := int(backOrder.Pending)
+		if shipped > onHand {
+			shipped = onHand
+		}
+		err = moveStock(tx, MoveSale, backOrder.SaleID, product_id, warehouse_id, -shipped)
+		if err != nil {
+			return err
+		}
+		onHand -= shipped
+		backOrder.Pending -= uint(shipped)
+		if backOrder.Pending == 0 {
+			now := time.Now()
+			backOrder.FulfilledAt = &now
+		}
+		err = tx.Save(&backOrder).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
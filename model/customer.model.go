@@ -1,16 +1,19 @@
 package model
 
-//This function allow obtain customers' resource.
-func GetCustomers() []Customer {
+import "github.com/jinzhu/gorm"
+
+//This function allow obtain a page of customers' resource.
+func GetCustomers(in ListQuery) ([]Customer, Page, error) {
+	normalRutFilter(in, "rut")
 	var customers []Customer
-	err = dbmap.Find(&customers).Error
-	checkErr(err, selectFailed)
-	return customers
+	page, err := list(&customers, in, AgentFields)
+	return customers, page, err
 }
 
 //This function allow obtain customer' resource for his id.
 func GetCustomer(rut string) (Customer, error) {
 	var customer Customer
+	rut = normalRut(rut)
 	customer.Rut = rut
 	err := dbmap.Where("rut=?", rut).First(&customer).Error
 	//err := dbmap.First(&customer, customer.Rut).Error
@@ -19,8 +22,17 @@ func GetCustomer(rut string) (Customer, error) {
 	return customer, err
 }
 
+//GetAnyCustomer return a customer with a rut, soft deleted ones included
+func GetAnyCustomer(rut string) (Customer, error) {
+	var customer Customer
+	err := dbmap.Unscoped().Where("rut = ?", normalRut(rut)).First(&customer).Error
+	checkErr(err, selectOneFailed)
+	return customer, err
+}
+
 //This function allow insert customer' resource
 func InsertCustomer(in *Customer) (*Customer, bool) {
+	in.Rut = normalRut(in.Rut)
 	err = dbmap.Create(in).Error
 	if err != nil {
 		return in, false
@@ -28,3 +40,70 @@ func InsertCustomer(in *Customer) (*Customer, bool) {
 		return in, true
 	}
 }
+
+//This function allow update customer' resource for his rut.
+func UpdateCustomer(rut string, in Customer) (Customer, error) {
+	customer, err := GetCustomer(rut)
+	if err != nil {
+		return customer, err
+	}
+	err = dbmap.Model(&customer).Updates(map[string]interface{}{
+		"name":  in.Name,
+		"mail":  in.Mail,
+		"phone": in.Phone,
+	}).Error
+	return customer, err
+}
+
+//This function allow soft delete customer' resource for his rut.
+func DeleteCustomer(rut string) (Customer, error) {
+	customer, err := GetCustomer(rut)
+	if err != nil {
+		return customer, err
+	}
+	err = dbmap.Delete(&customer).Error
+	return customer, err
+}
+
+//This function allow restore a soft deleted customer' resource.
+func RestoreCustomer(rut string) (Customer, error) {
+	rut = normalRut(rut)
+	res := dbmap.Unscoped().Model(&Customer{}).
+		Where("rut = ? AND deleted_at IS NOT NULL", rut).
+		UpdateColumn("deleted_at", gorm.Expr("NULL"))
+	if res.Error != nil {
+		return Customer{}, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return Customer{}, ErrNotDeleted
+	}
+	return GetCustomer(rut)
+}
+
+//This function allow delete permanently customer' resource,
+//it fails if sales reference it.
+func HardDeleteCustomer(rut string) error {
+	rut = normalRut(rut)
+	var count int
+	err := dbmap.Unscoped().Model(&Sale{}).
+		Where("customer_id = ?", rut).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrReferenced
+	}
+	tx := dbmap.Begin()
+	//Tags aren't history, they're removed with the customer
+	err = tx.Unscoped().Where("customer_id = ?", rut).Delete(&TagCustomer{}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Unscoped().Where("rut = ?", rut).Delete(&Customer{}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
@@ -6,7 +6,8 @@ func GetProductTotal(id string, in Date) ([]CustomerRecProd, error) {
 	err = dbmap.Raw("SELECT product.name, SUM(sale_detail.quantity) AS total"+
 		" FROM product, sale_detail, sale WHERE sale.customer_id=? AND sale.date>=? "+
 		"AND sale.date<=? AND sale_detail.sale_id=sale.id AND product.id="+
-		"sale_detail.product_id GROUP BY product.name ORDER BY total DESC",
+		"sale_detail.product_id"+in.warehouse("sale")+
+		" GROUP BY product.name ORDER BY total DESC",
 		id, in.Start, in.End).Scan(&products).Error
 	return products, err
 }
@@ -17,7 +18,7 @@ func GetTotalCash(id string, in Date) (CustomerCash, error) {
 	err = dbmap.Raw("SELECT SUM(sale_detail.quantity*sale_detail.price) AS cash"+
 		" FROM sale, sale_detail, customer WHERE customer.rut=? AND "+
 		"sale.customer_id=customer.rut AND sale.date >= ? AND sale.date"+
-		"<= ? AND sale_detail.sale_id=sale.id",
+		"<= ? AND sale_detail.sale_id=sale.id"+in.warehouse("sale"),
 		id, in.Start, in.End).Scan(&total_cash).Error
 	return total_cash, err
 }
@@ -27,7 +28,8 @@ func GetRankFrequency(k string, in Date) ([]CustomerFrecuency, error) {
 	duration := in.End.Sub(in.Start)
 	var customer_frecuency []CustomerFrecuency
 	err = dbmap.Raw("SELECT COUNT(sale.customer_id)::float/(?::float) as freq,"+
-		" customer.name as name FROM sale, customer"+
+		" customer.name as name FROM sale, customer WHERE"+
+		" customer.rut=sale.customer_id"+in.warehouse("sale")+
 		" GROUP BY customer_id, customer.name ORDER BY freq DESC LIMIT ?",
 		duration.Hours()/24/30, k).Scan(&customer_frecuency).Error
 	return customer_frecuency, err
@@ -6,7 +6,7 @@ func GetRankCustomerK(k string, in Date) ([]CustomerRankK, error) {
 	err = dbmap.Raw("SELECT customer.name, COUNT(sale.customer_id) AS count,"+
 		" SUM(sale_detail.quantity*sale_detail.price) AS cash FROM customer, sale,"+
 		" sale_detail WHERE sale.date>=? AND sale.date<=? AND customer.rut=sale."+
-		"customer_id AND sale_detail.sale_id=sale.id GROUP BY customer.name ORDER "+
+		"customer_id AND sale_detail.sale_id=sale.id"+in.warehouse("sale")+" GROUP BY customer.name ORDER "+
 		"BY cash DESC LIMIT ?", in.Start, in.End, k).Scan(&customers).Error
 	return customers, err
 }
@@ -18,10 +18,10 @@ func GetRankCustomerKL(k, l string, in Date) ([]CustomerRankKL, error) {
 		"quantity) AS cant  FROM customer, sale, sale_detail, (SELECT COUNT("+
 		"sale_detail.product_id) AS cantidad, sale_detail.product_id FROM "+
 		"sale_detail, sale WHERE sale.date >= ? AND sale.date <= ? AND sale_"+
-		"detail.sale_id=sale.id GROUP BY sale_detail.product_id ORDER BY cantidad"+
+		"detail.sale_id=sale.id"+in.warehouse("sale")+" GROUP BY sale_detail.product_id ORDER BY cantidad"+
 		" DESC LIMIT ?) AS products WHERE sale_detail.product_id=products."+
 		"product_id AND sale.id=sale_detail.sale_id AND sale.date>=? AND"+
-		" sale.date<=? AND customer.rut=sale.customer_id GROUP BY customer.rut"+
+		" sale.date<=? AND customer.rut=sale.customer_id"+in.warehouse("sale")+" GROUP BY customer.rut"+
 		" ORDER BY cant DESC LIMIT ?",
 		in.Start, in.End, l, in.Start, in.End, k).Scan(&customers).Error
 	return customers, err
@@ -33,7 +33,7 @@ func GetRankCustomerVariety(k string, in Date) ([]CustomerRankVariety, error) {
 	err = dbmap.Raw("SELECT customer.name, COUNT(sale_detail.product_id) as"+
 		" quantity FROM customer, sale_detail, sale WHERE sale.date>=? AND "+
 		"sale.date<=? AND customer.rut=sale.customer_id AND sale_detail.sale_id"+
-		"=sale.id GROUP BY customer.name ORDER BY quantity DESC LIMIT ?",
+		"=sale.id"+in.warehouse("sale")+" GROUP BY customer.name ORDER BY quantity DESC LIMIT ?",
 		in.Start, in.End, k).Scan(&customers).Error
 	return customers, err
 }
@@ -32,7 +32,12 @@ func initDb() *gorm.DB {
 	db.SingularTable(true)
 	db.AutoMigrate(Customer{}, Provider{}, Product{},
 		UserAcc{}, Tag{}, TagCustomer{}, Sale{},
-		SaleDetail{}, Purchase{}, PurchaseDetail{})
+		SaleDetail{}, Purchase{}, PurchaseDetail{},
+		RefreshToken{}, RevokedToken{}, ResetToken{},
+		Lockout{}, LoginAttempt{}, APIKey{}, RecoveryCode{},
+		AuditLog{}, StockMovement{}, BackOrder{},
+		StockCount{}, StockCountLine{}, Warehouse{},
+		Transfer{}, TransferDetail{})
 
 	db.Model(&TagCustomer{}).AddForeignKey("tag_id", "tag(id)",
 		"RESTRICT", "RESTRICT")
@@ -57,6 +62,47 @@ func initDb() *gorm.DB {
 	db.Model(&PurchaseDetail{}).AddForeignKey("product_id", "product(id)",
 		"RESTRICT", "RESTRICT")
 
+	db.Model(&StockMovement{}).AddForeignKey("product_id", "product(id)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&BackOrder{}).AddForeignKey("sale_id", "sale(id)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&BackOrder{}).AddForeignKey("product_id", "product(id)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&BackOrder{}).AddForeignKey("customer_id", "customer(rut)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&StockCountLine{}).AddForeignKey("stock_count_id", "stock_count(id)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&StockCountLine{}).AddForeignKey("product_id", "product(id)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&Transfer{}).AddForeignKey("from_id", "warehouse(id)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&Transfer{}).AddForeignKey("to_id", "warehouse(id)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&TransferDetail{}).AddForeignKey("transfer_id", "transfer(id)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&TransferDetail{}).AddForeignKey("product_id", "product(id)",
+		"RESTRICT", "RESTRICT")
+	initWarehouses(db)
+	backfillStock(db)
+	db.Exec("DROP VIEW IF EXISTS product_reorder")
+	db.Exec("DROP VIEW IF EXISTS product_stock")
+	db.Exec(productStockView)
+	db.Exec(productReorderView)
+	db.Exec("DROP VIEW IF EXISTS warehouse_stock")
+	db.Exec(warehouseStockView)
+
+	//Sales with their amounts, it's created again
+	//because the columns of sale can change
+	db.Exec("DROP VIEW IF EXISTS sale_summary")
+	db.Exec(saleSummaryView())
+
+	db.Model(&RefreshToken{}).AddForeignKey("user_id", "user_acc(mail)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&ResetToken{}).AddForeignKey("user_id", "user_acc(mail)",
+		"RESTRICT", "RESTRICT")
+	db.Model(&RecoveryCode{}).AddForeignKey("user_id", "user_acc(mail)",
+		"RESTRICT", "RESTRICT")
+
 	//Create admin account
 	var in UserAcc
 	in.Name = os.Getenv("NAME")
@@ -64,7 +110,7 @@ func initDb() *gorm.DB {
 	in.Mail = os.Getenv("MAIL")
 	hash_pass, _ := hash.HashPassword(os.Getenv("PASSWORD"))
 	in.Pass = hash_pass
-	in.Rut = os.Getenv("RUT")
+	in.Rut = normalRut(os.Getenv("RUT"))
 	role, _ := strconv.ParseInt(os.Getenv("ROLE"), 10, 8)
 	in.Role = int8(role)
 	in.Active = true
@@ -1,8 +1,23 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
+//Date is the period of the stats, they're of
+//a warehouse if WarehouseID isn't zero
 type Date struct {
-	Start time.Time `json:"start" binding:"required"`
-	End   time.Time `json:"end" binding:"required"`
+	Start       time.Time `json:"start" binding:"required"`
+	End         time.Time `json:"end" binding:"required,gtefield=Start"`
+	WarehouseID uint      `json:"warehouse_id"`
+}
+
+//warehouse return the SQL condition of the warehouse of the stats
+//over the sale or purchase table, it's empty for every warehouse
+func (d Date) warehouse(table string) string {
+	if d.WarehouseID == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" AND %s.warehouse_id = %d", table, d.WarehouseID)
 }
@@ -0,0 +1,162 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+//ErrCursor is returned when the cursor of a list isn't valid
+var ErrCursor = errors.New("The cursor isn't valid")
+
+//ListFields represents the columns of a table that can be
+//sorted and filtered in a list, Key is the column that breaks ties
+//and Date and Amount, if they're set, the columns filtered by range
+type ListFields struct {
+	Key     string
+	Date    string
+	Amount  string
+	Columns []string
+}
+
+//Has return true if the column can be sorted and filtered
+func (f ListFields) Has(column string) bool {
+	for _, c := range f.Columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+//ListQuery represents pagination, sorting and filtering of a list.
+//Filters are matched by equality, or by prefix if the value ends with '*',
+//Start and End limit the date range and Min and Max the amount range.
+//When Cursor is set Offset is ignored
+type ListQuery struct {
+	Limit   int
+	Offset  int
+	Cursor  string
+	Sort    string
+	Desc    bool
+	Filters map[string]string
+	Start   *time.Time
+	End     *time.Time
+	Min     *int
+	Max     *int
+	Deleted bool
+}
+
+//Page represents the position of a list, Next is the
+//cursor of the following page, empty in the last one
+type Page struct {
+	Total  int    `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Next   string `json:"next,omitempty"`
+}
+
+//list fills 'out', a pointer to a slice of a table,
+//with the rows that match the query
+func list(out interface{}, in ListQuery, fields ListFields) (Page, error) {
+	page := Page{Limit: in.Limit, Offset: in.Offset}
+	sort := in.Sort
+	if sort == "" {
+		sort = fields.Key
+	}
+	query := dbmap
+	if in.Deleted {
+		query = query.Unscoped()
+	}
+	for column, value := range in.Filters {
+		if strings.HasSuffix(value, "*") {
+			query = query.Where(column+" ILIKE ?", likePrefix(strings.TrimSuffix(value, "*")))
+		} else {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+	if in.Start != nil {
+		query = query.Where(fields.Date+" >= ?", *in.Start)
+	}
+	if in.End != nil {
+		query = query.Where(fields.Date+" <= ?", *in.End)
+	}
+	if in.Min != nil {
+		query = query.Where(fields.Amount+" >= ?", *in.Min)
+	}
+	if in.Max != nil {
+		query = query.Where(fields.Amount+" <= ?", *in.Max)
+	}
+	err := query.Model(out).Count(&page.Total).Error
+	if err != nil {
+		checkErr(err, countFailed)
+		return page, err
+	}
+
+	direction, compare := " ASC", " > "
+	if in.Desc {
+		direction, compare = " DESC", " < "
+	}
+	query = query.Order(sort + direction)
+	if sort != fields.Key {
+		query = query.Order(fields.Key + direction)
+	}
+	if in.Cursor != "" {
+		values, err := decodeCursor(in.Cursor)
+		if err != nil {
+			return page, err
+		}
+		if sort == fields.Key {
+			query = query.Where(sort+compare+"?", values[1])
+		} else {
+			query = query.Where("("+sort+", "+fields.Key+")"+compare+"(?, ?)", values[0], values[1])
+		}
+		page.Offset = 0
+	} else {
+		query = query.Offset(in.Offset)
+	}
+	err = query.Limit(in.Limit).Find(out).Error
+	if err != nil {
+		checkErr(err, selectFailed)
+		return page, err
+	}
+
+	rows := reflect.ValueOf(out).Elem()
+	if rows.Len() == in.Limit && (in.Cursor != "" || page.Offset+rows.Len() < page.Total) {
+		last := dbmap.NewScope(rows.Index(rows.Len() - 1).Addr().Interface())
+		sortField, _ := last.FieldByName(sort)
+		keyField, _ := last.FieldByName(fields.Key)
+		page.Next = encodeCursor(sortField.Field.Interface(), keyField.Field.Interface())
+	}
+	return page, nil
+}
+
+//likePrefix escapes the LIKE wildcards of the prefix
+func likePrefix(prefix string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(prefix) + "%"
+}
+
+//encodeCursor return the sort and key values of the last row as cursor
+func encodeCursor(sort, key interface{}) string {
+	doc, _ := json.Marshal([]interface{}{sort, key})
+	return base64.RawURLEncoding.EncodeToString(doc)
+}
+
+//decodeCursor return the sort and key values of the cursor
+func decodeCursor(cursor string) ([]interface{}, error) {
+	doc, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrCursor
+	}
+	var values []interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(doc)))
+	decoder.UseNumber()
+	if decoder.Decode(&values) != nil || len(values) != 2 {
+		return nil, ErrCursor
+	}
+	return values, nil
+}
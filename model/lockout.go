@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+//Lockout represents the failed sign in attempts of an account or an IP,
+//Key is "mail:<mail>" or "ip:<ip>"
+type Lockout struct {
+	Key         string    `json:"key" gorm:"primary_key"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"locked_until"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//LoginAttempt represents an entry of the sign in history
+type LoginAttempt struct {
+	ID        uint   `json:"id" gorm:"primary_key"`
+	Mail      string `json:"mail" gorm:"index"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	Success   bool   `json:"success"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+//LockoutPolicy defines when failed attempts delay or lock sign in
+type LockoutPolicy struct {
+	//Failures allowed without delay
+	Free int
+	//Failures that lock sign in
+	Max int
+	//Time that sign in stays locked
+	LockTime time.Duration
+	//Failures older than this are forgotten
+	Window time.Duration
+}
+
+//Policies of accounts and IPs, an IP can try several accounts
+var (
+	AccountPolicy = LockoutPolicy{Free: 3, Max: 10,
+		LockTime: time.Minute * 15, Window: time.Minute * 15}
+	IPPolicy = LockoutPolicy{Free: 10, Max: 50,
+		LockTime: time.Minute * 15, Window: time.Minute * 15}
+)
@@ -0,0 +1,98 @@
+package model
+
+import "time"
+
+//Maximum delay between attempts before the lock
+var maxLoginDelay = time.Minute
+
+//MailKey returns the lockout key of an account
+func MailKey(mail string) string {
+	return "mail:" + mail
+}
+
+//IPKey returns the lockout key of an IP
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+//LoginLocked returns the time until sign in is locked for these keys,
+//and true if it's locked now
+func LoginLocked(keys ...string) (time.Time, bool) {
+	var until time.Time
+	var lockouts []Lockout
+	err := dbmap.Where("key IN (?) AND locked_until > ?",
+		keys, time.Now()).Find(&lockouts).Error
+	checkErr(err, selectFailed)
+	for _, lockout := range lockouts {
+		if lockout.LockedUntil.After(until) {
+			until = lockout.LockedUntil
+		}
+	}
+	return until, !until.IsZero()
+}
+
+//LoginFailed registers a failed attempt to a key, after the free
+//attempts each failure doubles the delay until the key is locked
+func LoginFailed(key string, policy LockoutPolicy) (Lockout, error) {
+	var lockout Lockout
+	now := time.Now()
+	err := dbmap.Where(Lockout{Key: key}).FirstOrInit(&lockout).Error
+	if err != nil {
+		return lockout, err
+	}
+	if lockout.UpdatedAt.Before(now.Add(-policy.Window)) &&
+		lockout.LockedUntil.Before(now) {
+		lockout.Failures = 0
+	}
+	lockout.Failures++
+	if lockout.Failures >= policy.Max {
+		lockout.LockedUntil = now.Add(policy.LockTime)
+	} else if lockout.Failures > policy.Free {
+		delay := time.Second << uint(lockout.Failures-policy.Free-1)
+		if delay > maxLoginDelay {
+			delay = maxLoginDelay
+		}
+		lockout.LockedUntil = now.Add(delay)
+	}
+	err = dbmap.Save(&lockout).Error
+	return lockout, err
+}
+
+//GetLockout returns the failed attempts of a key
+func GetLockout(key string) (Lockout, error) {
+	var lockout Lockout
+	err := dbmap.Where("key = ?", key).First(&lockout).Error
+	return lockout, err
+}
+
+//ClearLockout removes the failed attempts of a key
+func ClearLockout(key string) error {
+	return dbmap.Where("key = ?", key).Delete(Lockout{}).Error
+}
+
+//GetLockouts returns keys with failed attempts
+func GetLockouts() ([]Lockout, error) {
+	var lockouts []Lockout
+	err := dbmap.Order("locked_until DESC").Find(&lockouts).Error
+	checkErr(err, selectFailed)
+	return lockouts, err
+}
+
+//InsertLoginAttempt insert an entry in the sign in history
+func InsertLoginAttempt(in *LoginAttempt) (*LoginAttempt, error) {
+	err = dbmap.Create(in).Error
+	return in, err
+}
+
+//GetLoginAttempts returns the last sign in attempts,
+//filtered by mail if it isn't empty
+func GetLoginAttempts(mail string, limit int) ([]LoginAttempt, error) {
+	var attempts []LoginAttempt
+	query := dbmap.Order("created_at DESC").Limit(limit)
+	if mail != "" {
+		query = query.Where("mail = ?", mail)
+	}
+	err := query.Find(&attempts).Error
+	checkErr(err, selectFailed)
+	return attempts, err
+}
@@ -5,3 +5,36 @@ type Login struct {
 	Mail string `json:"mail" binding:"required"`
 	Pass string `json:"pass" binding:"required"`
 }
+
+//Represent refresh token input
+type Refresh struct {
+	Token string `json:"refresh_token"`
+}
+
+//Represent password change input
+type PasswordChange struct {
+	OldPass string `json:"old_pass" binding:"required"`
+	Pass    string `json:"pass" binding:"required"`
+}
+
+//Represent forgot password input
+type PasswordForgot struct {
+	Mail string `json:"mail" binding:"required,email"`
+}
+
+//Represent password reset input
+type PasswordReset struct {
+	Token string `json:"token" binding:"required"`
+	Pass  string `json:"pass" binding:"required"`
+}
+
+//Represent second factor challenge input
+type Challenge struct {
+	Challenge string `json:"challenge" binding:"required"`
+	Code      string `json:"code"`
+}
+
+//Represent second factor code input
+type TOTPCode struct {
+	Code string `json:"code" binding:"required"`
+}
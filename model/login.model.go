@@ -9,8 +9,8 @@ func LoginP(in Login) (UserAcc, bool) {
 	if err == nil {
 		//Check password and hash
 		flag := hash.CheckPasswordHash(in.Pass, user_acc.Pass)
-		//Password and hash are equals
-		if flag {
+		//Password and hash are equals and account isn't deactivated
+		if flag && user_acc.Active {
 			return user_acc, true
 		}
 	}
@@ -1,9 +1,21 @@
 package model
 
+import "errors"
+
 //Messages to model
 var (
 	ErrorAdminAccount = "Error creating admin account."
 	selectOneFailed   = "Error selecting one row"
 	selectFailed      = "Error selecting rows"
 	countFailed       = "Error in select count"
+	deleteFailed      = "Error deleting rows"
+	updateFailed      = "Error updating rows"
+)
+
+//Errors to model
+var (
+	ErrReferenced = errors.New("The resource is referenced by other resources")
+	ErrNotDeleted = errors.New("The resource is not deleted")
+	ErrMissing    = errors.New("A referenced resource doesn't exist")
+	ErrClosed     = errors.New("The resource is closed")
 )
@@ -10,6 +10,18 @@ type Product struct {
 	Details  string `json:"details" binding:"required"`
 	Brand    string `json:"brand" binding:"required"`
 	Category string `json:"category" binding:"required"`
+	//Reorder point, products with less stock on hand are reordered
+	MinStock        uint `json:"min_stock"`
+	ReorderQuantity uint `json:"reorder_quantity"`
+	//The reorder hook was called since the stock went below MinStock
+	ReorderAlerted bool `json:"-"`
+}
+
+//ProductFields are the columns of products lists
+var ProductFields = ListFields{
+	Key: "id",
+	Columns: []string{"id", "name", "details", "brand", "category",
+		"min_stock", "reorder_quantity", "created_at", "updated_at"},
 }
 
 type InfoProduct struct {
@@ -1,11 +1,12 @@
 package model
 
-//This function allow obtain products' resource.
-func GetProducts() []Product {
+import "github.com/jinzhu/gorm"
+
+//This function allow obtain a page of products' resource.
+func GetProducts(in ListQuery) ([]Product, Page, error) {
 	var products []Product
-	err = dbmap.Find(&products).Error
-	checkErr(err, selectFailed)
-	return products
+	page, err := list(&products, in, ProductFields)
+	return products, page, err
 }
 
 //This function allow obtain product' resource for his id.
@@ -17,12 +18,83 @@ func GetProduct(id uint) (Product, error) {
 	return product, err
 }
 
+//GetAnyProduct return a product with an ID, soft deleted ones included
+func GetAnyProduct(id uint) (Product, error) {
+	var product Product
+	err := dbmap.Unscoped().First(&product, id).Error
+	checkErr(err, selectOneFailed)
+	return product, err
+}
+
 //This function allow insert product' resource
 func InsertProduct(in *Product) (*Product, bool) {
 	err = dbmap.Create(in).Error
 	if err != nil {
 		return in, false
 	} else {
+		checkReorder(in.ID)
 		return in, true
 	}
 }
+
+//This function allow update product' resource for his id.
+func UpdateProduct(id uint, in Product) (Product, error) {
+	product, err := GetProduct(id)
+	if err != nil {
+		return product, err
+	}
+	err = dbmap.Model(&product).Updates(map[string]interface{}{
+		"name":             in.Name,
+		"details":          in.Details,
+		"brand":            in.Brand,
+		"category":         in.Category,
+		"min_stock":        in.MinStock,
+		"reorder_quantity": in.ReorderQuantity,
+	}).Error
+	if err == nil {
+		checkReorder(id)
+	}
+	return product, err
+}
+
+//This function allow soft delete product' resource for his id.
+func DeleteProduct(id uint) (Product, error) {
+	product, err := GetProduct(id)
+	if err != nil {
+		return product, err
+	}
+	err = dbmap.Delete(&product).Error
+	return product, err
+}
+
+//This function allow restore a soft deleted product' resource.
+func RestoreProduct(id uint) (Product, error) {
+	res := dbmap.Unscoped().Model(&Product{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		UpdateColumn("deleted_at", gorm.Expr("NULL"))
+	if res.Error != nil {
+		return Product{}, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return Product{}, ErrNotDeleted
+	}
+	return GetProduct(id)
+}
+
+//This function allow delete permanently product' resource,
+//it fails if sale, purchase or transfer details, stock movements,
+//counts or back-orders reference it.
+func HardDeleteProduct(id uint) error {
+	for _, ref := range []interface{}{&SaleDetail{}, &PurchaseDetail{},
+		&TransferDetail{}, &StockMovement{}, &StockCountLine{}, &BackOrder{}} {
+		var count int
+		err := dbmap.Model(ref).Where("product_id = ?", id).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrReferenced
+		}
+	}
+	return dbmap.Unscoped().Where("id = ?", id).Delete(&Product{}).Error
+}
@@ -6,7 +6,7 @@ func GetRankProductK(k string, in Date) ([]ProductK, error) {
 	err = dbmap.Raw("SELECT product.*, cant FROM product, (SELECT SUM(sale_detail."+
 		"quantity) AS cant, sale_detail.product_id FROM sale_detail, sale WHERE "+
 		"sale.date>=? AND sale.date<=? AND "+
-		"sale_detail.sale_id=sale.id GROUP BY sale_detail.product_id "+
+		"sale_detail.sale_id=sale.id"+in.warehouse("sale")+" GROUP BY sale_detail.product_id "+
 		"ORDER BY cant DESC ) AS cant_prod WHERE "+
 		"product.id=cant_prod.product_id LIMIT ?", in.Start, in.End, k).Scan(&products).Error
 	return products, err
@@ -18,7 +18,7 @@ func GetRankProductCategoryS(category, k string, in Date) ([]ProductRankCategory
 	err = dbmap.Raw("SELECT product.id, product.name , SUM(sale_detail.quantity) AS "+
 		"total FROM  sale_detail, sale, product WHERE sale.date>=? AND "+
 		"sale.date<=? AND sale_detail.sale_id= sale.id AND product.id="+
-		"sale_detail.product_id AND product.category=? GROUP BY "+
+		"sale_detail.product_id AND product.category=?"+in.warehouse("sale")+" GROUP BY "+
 		"product.id ORDER BY total DESC"+
 		" LIMIT ?",
 		in.Start, in.End, category, k).Scan(&products).Error
@@ -32,7 +32,7 @@ func GetRankProductCategoryP(category, k string, in Date) ([]ProductRankCategory
 		"quantity) AS total FROM  purchase_detail, "+
 		"purchase, product WHERE product.category=? AND purchase.date>=? AND"+
 		" purchase.date<=? AND purchase_detail.purchase_id= purchase.id AND"+
-		" purchase_detail.product_id=product.id GROUP BY product.id "+
+		" purchase_detail.product_id=product.id"+in.warehouse("purchase")+" GROUP BY product.id "+
 		"ORDER BY total DESC LIMIT ?",
 		category, in.Start, in.End, k).Scan(&products).Error
 	return products, err
@@ -45,7 +45,7 @@ func GetRankProductBrand(brand, k string, in Date) ([]InfoProduct, error) {
 		"product_id) AS sales ,SUM(sale_detail.quantity) AS total FROM product,"+
 		" sale_detail, sale WHERE sale.date>=? AND sale.date<=?"+
 		" AND sale_detail.sale_id=sale.id AND product.brand=? AND "+
-		"sale_detail.product_id=product.id GROUP BY product.id ORDER BY total DESC"+
+		"sale_detail.product_id=product.id"+in.warehouse("sale")+" GROUP BY product.id ORDER BY total DESC"+
 		" LIMIT ?",
 		in.Start, in.End, brand, k).Scan(&products).Error
 	return products, err
@@ -62,7 +62,7 @@ func GetRankProfitability(k string, in Date) ([]ProductRankProfitability, error)
 		" = purchase.id AND sale.date >= ? AND sale.date<= ? AND"+
 		" sale_detail.sale_id=sale.id AND purchase_detail.purchase_id=purchase.id"+
 		" AND sale_detail.product_id=product.id AND purchase_detail.product_id"+
-		"=product.id GROUP BY product.name, product.id) AS avg_product"+
+		"=product.id"+in.warehouse("sale")+in.warehouse("purchase")+" GROUP BY product.name, product.id) AS avg_product"+
 		" ORDER BY rent DESC LIMIT ?",
 		in.Start, in.End, in.Start, in.End, k).Scan(&products).Error
 	return products, err
@@ -74,7 +74,7 @@ func GetRankProductPP(id string, in Date) ([]ProductRankProviderPrice, error) {
 	err = dbmap.Raw("SELECT provider.name, provider.mail, provider.phone, purchase_detail.price FROM provider,"+
 		" purchase, purchase_detail WHERE purchase.date>=? AND purchase.date<=?"+
 		" AND purchase_detail.product_id=? AND purchase.id="+
-		"purchase_detail.purchase_id AND provider.rut=purchase.provider_id GROUP"+
+		"purchase_detail.purchase_id AND provider.rut=purchase.provider_id"+in.warehouse("purchase")+" GROUP"+
 		" BY provider.name, purchase_detail.price, provider.mail, provider.phone ORDER BY purchase_detail.price DESC",
 		in.Start, in.End, id).Scan(&products).Error
 	return products, err
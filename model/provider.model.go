@@ -1,24 +1,36 @@
 package model
 
-//This function allow obtain provider' resource.
-func GetProviders() []Provider {
-	var provider []Provider
-	err = dbmap.Find(&provider).Error
-	checkErr(err, selectFailed)
-	return provider
+import "github.com/jinzhu/gorm"
+
+//This function allow obtain a page of providers' resource.
+func GetProviders(in ListQuery) ([]Provider, Page, error) {
+	normalRutFilter(in, "rut")
+	var providers []Provider
+	page, err := list(&providers, in, AgentFields)
+	return providers, page, err
 }
 
 //This function allow obtain provider' resource for his id.
 func GetProvider(rut string) (Provider, error) {
 	var provider Provider
+	rut = normalRut(rut)
 	provider.Rut = rut
 	err := dbmap.Where("rut=?", rut).First(&provider).Error
 	checkErr(err, selectOneFailed)
 	return provider, err
 }
 
+//GetAnyProvider return a provider with a rut, soft deleted ones included
+func GetAnyProvider(rut string) (Provider, error) {
+	var provider Provider
+	err := dbmap.Unscoped().Where("rut = ?", normalRut(rut)).First(&provider).Error
+	checkErr(err, selectOneFailed)
+	return provider, err
+}
+
 //This function allow insert provider' resource
 func InsertProvider(in *Provider) (*Provider, bool) {
+	in.Rut = normalRut(in.Rut)
 	err = dbmap.Create(in).Error
 	if err != nil {
 		return in, false
@@ -26,3 +38,64 @@ func InsertProvider(in *Provider) (*Provider, bool) {
 		return in, true
 	}
 }
+
+//This function allow update provider' resource for his rut.
+func UpdateProvider(rut string, in Provider) (Provider, error) {
+	provider, err := GetProvider(rut)
+	if err != nil {
+		return provider, err
+	}
+	err = dbmap.Model(&provider).Updates(map[string]interface{}{
+		"name":  in.Name,
+		"mail":  in.Mail,
+		"phone": in.Phone,
+	}).Error
+	return provider, err
+}
+
+//This function allow soft delete provider' resource for his rut.
+func DeleteProvider(rut string) (Provider, error) {
+	provider, err := GetProvider(rut)
+	if err != nil {
+		return provider, err
+	}
+	err = dbmap.Delete(&provider).Error
+	return provider, err
+}
+
+//This function allow restore a soft deleted provider' resource.
+func RestoreProvider(rut string) (Provider, error) {
+	rut = normalRut(rut)
+	res := dbmap.Unscoped().Model(&Provider{}).
+		Where("rut = ? AND deleted_at IS NOT NULL", rut).
+		UpdateColumn("deleted_at", gorm.Expr("NULL"))
+	if res.Error != nil {
+		return Provider{}, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return Provider{}, ErrNotDeleted
+	}
+	return GetProvider(rut)
+}
+
+//This function allow delete permanently provider' resource,
+//it fails if purchases reference it.
+func HardDeleteProvider(rut string) error {
+	rut = normalRut(rut)
+	var count int
+	err := dbmap.Unscoped().Model(&Purchase{}).
+		Where("provider_id = ?", rut).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrReferenced
+	}
+	tx := dbmap.Begin()
+	err = tx.Unscoped().Where("rut = ?", rut).Delete(&Provider{}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
@@ -5,7 +5,7 @@ func GetRankProviderK(k string, in Date) ([]ProviderRankK, error) {
 	var providers []ProviderRankK
 	err = dbmap.Raw("SELECT provider.name , AVG(date_part('day', purchase."+
 		"ship_time)) AS days FROM purchase, provider WHERE purchase.date>=? AND"+
-		" purchase.date<= ? AND provider.rut=purchase.provider_id GROUP BY"+
+		" purchase.date<= ? AND provider.rut=purchase.provider_id"+in.warehouse("purchase")+" GROUP BY"+
 		" provider.name  ORDER BY days LIMIT ?",
 		in.Start, in.End, k).Scan(&providers).Error
 	return providers, err
@@ -17,7 +17,7 @@ func GetRankProviderPP(k, id string, in Date) ([]ProviderRankPP, error) {
 	err = dbmap.Raw("SELECT product.name, purchase_detail.price FROM product,"+
 		" purchase, purchase_detail WHERE purchase.date>=? AND purchase.date<= ?"+
 		" AND purchase.provider_id=? AND purchase_detail.purchase_id=purchase.id"+
-		" AND product.id=purchase_detail.product_id GROUP BY product.name, "+
+		" AND product.id=purchase_detail.product_id"+in.warehouse("purchase")+" GROUP BY product.name, "+
 		"purchase_detail.price ORDER BY purchase_detail.price DESC LIMIT ?",
 		in.Start, in.End, id, k).Scan(&products).Error
 	return products, err
@@ -30,7 +30,7 @@ func GetRankProviderVariety(k string, in Date) ([]ProviderRankVariety, error) {
 		" COUNT(purchase_detail.product_id) AS"+
 		" quantity FROM provider, purchase_detail, purchase WHERE purchase.date"+
 		" >= ? AND purchase.date <= ? AND provider.rut = purchase.provider_id AND"+
-		" purchase_detail.purchase_id = purchase.id GROUP BY provider.name,"+
+		" purchase_detail.purchase_id = purchase.id"+in.warehouse("purchase")+" GROUP BY provider.name,"+
 		" provider.phone, provider.mail ORDER BY quantity DESC LIMIT ?",
 		in.Start, in.End, k).Scan(&providers).Error
 	return providers, err
@@ -6,9 +6,68 @@ import "github.com/jinzhu/gorm"
 //This struct represent purchase model
 type Purchase struct {
 	gorm.Model
-	ProviderID string    `json:"id_provider" binding:"required"`
+	ProviderID string    `json:"id_provider" binding:"required,rut"`
 	Date       time.Time `json:"date" binding:"required"`
-	ShipTime   time.Time `json:"shiptime" binding:"required"`
+	ShipTime   time.Time `json:"shiptime" binding:"required,gtefield=Date"`
+	//Warehouse where it's received, the default one if it's zero
+	WarehouseID uint `json:"warehouse_id"`
+}
+
+//PurchaseFields are the columns of purchases lists
+var PurchaseFields = ListFields{
+	Key:     "id",
+	Date:    "date",
+	Columns: []string{"id", "provider_id", "warehouse_id", "date", "ship_time", "created_at"},
+}
+
+//PurchaseDoc represents a purchase with its line items,
+//Quantity and Total are computed from the line items
+type PurchaseDoc struct {
+	Purchase
+	Details  []PurchaseLine `json:"details" binding:"required,min=1,unique=product_id,dive"`
+	Quantity uint           `json:"quantity"`
+	Total    uint           `json:"total"`
+}
+
+//PurchaseLine represents a line item of a purchase document,
+//the purchase is set when it's inserted
+type PurchaseLine struct {
+	PurchaseID  uint   `json:"purchase_id"`
+	ProductID   uint   `json:"product_id" binding:"required"`
+	Price       uint   `json:"price" binding:"gt=0"`
+	Quantity    uint   `json:"quantity" binding:"gt=0"`
+	ProductName string `json:"product_name"`
+	Total       uint   `json:"total"`
+}
+
+//Detail return the purchase_detail row of the line item
+func (l PurchaseLine) Detail() PurchaseDetail {
+	return PurchaseDetail{
+		PurchaseID: l.PurchaseID,
+		ProductID:  l.ProductID,
+		Price:      l.Price,
+		Quantity:   l.Quantity,
+	}
+}
+
+//Sum computes the totals of the line items and the purchase
+func (p *PurchaseDoc) Sum() {
+	p.Quantity, p.Total = 0, 0
+	for i := range p.Details {
+		line := &p.Details[i]
+		line.Total = line.Price * line.Quantity
+		p.Quantity += line.Quantity
+		p.Total += line.Total
+	}
+}
+
+//productIDs return the products of the line items
+func (p *PurchaseDoc) productIDs() []uint {
+	products := make([]uint, len(p.Details))
+	for i, line := range p.Details {
+		products[i] = line.ProductID
+	}
+	return products
 }
 
 //This struct is to models
@@ -1,21 +1,86 @@
 package model
 
-//GetPurchase insert a purchase in database
-func GetPurchase(provider_id string) (Purchase, error) {
-	var purchase Purchase
-	purchase.ProviderID = provider_id
-	err := dbmap.Where("provider_id = $1",
-		purchase.ProviderID).First(&purchase).Error
-	checkErr(err, selectOneFailed)
-	return purchase, err
+//GetPurchases return a page of purchases
+func GetPurchases(in ListQuery) ([]Purchase, Page, error) {
+	normalRutFilter(in, "provider_id")
+	var purchases []Purchase
+	page, err := list(&purchases, in, PurchaseFields)
+	return purchases, page, err
 }
 
-//InsertPurchase insert a purchase in database
-func InsertPurchase(in *Purchase) (*Purchase, bool) {
-	err = dbmap.Create(in).Error
+//GetPurchase return a purchase with its line items for its id
+func GetPurchase(id uint) (PurchaseDoc, error) {
+	var doc PurchaseDoc
+	err := dbmap.First(&doc.Purchase, id).Error
 	if err != nil {
-		return in, false
-	} else {
-		return in, true
+		checkErr(err, selectOneFailed)
+		return doc, err
 	}
+	err = dbmap.Table("purchase_detail").
+		Select("purchase_detail.*, product.name AS product_name").
+		Joins("JOIN product ON product.id = purchase_detail.product_id").
+		Where("purchase_detail.purchase_id = ?", id).
+		Order("purchase_detail.product_id").
+		Scan(&doc.Details).Error
+	checkErr(err, selectFailed)
+	doc.Sum()
+	return doc, err
+}
+
+//InsertPurchaseDoc insert a purchase and its line items in one transaction,
+//nothing is inserted if the provider, the warehouse or any product doesn't
+//exist. The received products fulfill their pending back-orders
+func InsertPurchaseDoc(in *PurchaseDoc) error {
+	in.ProviderID = normalRut(in.ProviderID)
+	tx := dbmap.Begin()
+	var count int
+	err := tx.Model(&Provider{}).Where("rut = ?", in.ProviderID).Count(&count).Error
+	if err == nil && count == 0 {
+		err = ErrMissing
+	}
+	if err == nil {
+		in.WarehouseID, err = checkWarehouse(tx, in.WarehouseID)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	products := in.productIDs()
+	if len(products) > 0 {
+		err = tx.Model(&Product{}).Where("id IN (?)", products).Count(&count).Error
+		if err == nil && count != len(products) {
+			err = ErrMissing
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	err = tx.Create(&in.Purchase).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range in.Details {
+		in.Details[i].PurchaseID = in.ID
+		detail := in.Details[i].Detail()
+		err = tx.Create(&detail).Error
+		if err == nil {
+			err = moveStock(tx, MovePurchase, in.ID, detail.ProductID, in.WarehouseID,
+				int(detail.Quantity))
+		}
+		if err == nil {
+			err = fulfillBackOrders(tx, detail.ProductID, in.WarehouseID)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	in.Sum()
+	err = tx.Commit().Error
+	if err == nil {
+		checkReorder(products...)
+	}
+	return err
 }
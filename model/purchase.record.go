@@ -7,6 +7,7 @@ func GetPurchasesProduct(id string, in Date) ([]PurchasesProductRec, error) {
 		" FROM provider, purchase_detail, purchase WHERE purchase.date>=? AND"+
 		" purchase.date<= ? AND purchase_detail.product_id=? AND purchase.id"+
 		"=purchase_detail.purchase_id AND provider.rut=purchase.provider_id"+
+		in.warehouse("purchase")+
 		" ORDER BY purchase.date DESC",
 		in.Start, in.End, id).Scan(&purchases).Error
 	return purchases, err
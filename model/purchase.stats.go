@@ -7,7 +7,7 @@ func GetRankPurchasesCP(category, k string, in Date) ([]ProductRankCategory, err
 		"purchase_detail.price) AS total FROM (SELECT * FROM product WHERE "+
 		"category=? ) AS products, purchase, purchase_detail WHERE"+
 		" purchase.date>=? AND purchase.date<=? AND purchase_detail.purchase_id"+
-		"=purchase.id AND products.id=purchase_detail.product_id GROUP BY"+
+		"=purchase.id AND products.id=purchase_detail.product_id"+in.warehouse("purchase")+" GROUP BY"+
 		" products.id, products.name ORDER BY total DESC LIMIT ?",
 		category, in.Start, in.End, k).Scan(&products).Error
 	return products, err
@@ -23,7 +23,7 @@ func GetRankPurchasesK(k string, in Date) ([]PurchaseRankK, error) {
 		"provider, product, purchase, purchase_detail WHERE purchase.date >= ?"+
 		" AND purchase.date <= ? AND purchase.provider_id = provider.rut AND"+
 		" purchase_detail.purchase_id=purchase.id AND product.id="+
-		"purchase_detail.product_id GROUP BY provider.name, product.name, "+
+		"purchase_detail.product_id"+in.warehouse("purchase")+" GROUP BY provider.name, product.name, "+
 		"purchase_detail.quantity, purchase_detail.price, purchase.id ORDER"+
 		" BY total DESC LIMIT ?",
 		in.Start, in.End, k).Scan(&purchases).Error
@@ -36,7 +36,7 @@ func GetRankPurchasesProduct(k string, in Date) ([]PurchaseRankProduct, error) {
 	err = dbmap.Raw("SELECT SUM(purchase_detail.quantity) as cash, product.name"+
 		" FROM product, purchase_detail, purchase WHERE purchase.date >= ? AND"+
 		" purchase.date <= ? AND purchase_detail.purchase_id = purchase.id AND"+
-		" product.id = purchase_detail.product_id GROUP BY product.name ORDER"+
+		" product.id = purchase_detail.product_id"+in.warehouse("purchase")+" GROUP BY product.name ORDER"+
 		" BY cash DESC LIMIT ?",
 		in.Start, in.End, k).Scan(&products).Error
 	return products, err
@@ -12,12 +12,29 @@ func GetPurchaseDetail(purchase_id, product_id uint) (PurchaseDetail, error) {
 	return purchase_detail, err
 }
 
-//InsertPurchaseDetail insert a purchase_detail in database
+//InsertPurchaseDetail insert a purchase_detail and its stock movement
+//in database, the received product fulfills its pending back-orders
 func InsertPurchaseDetail(in *PurchaseDetail) (*PurchaseDetail, bool) {
-	err = dbmap.Create(in).Error
+	tx := dbmap.Begin()
+	var purchase Purchase
+	err := tx.First(&purchase, in.PurchaseID).Error
+	if err == nil {
+		err = tx.Create(in).Error
+	}
+	if err == nil {
+		err = moveStock(tx, MovePurchase, in.PurchaseID, in.ProductID, purchase.WarehouseID,
+			int(in.Quantity))
+	}
+	if err == nil {
+		err = fulfillBackOrders(tx, in.ProductID, purchase.WarehouseID)
+	}
 	if err != nil {
+		tx.Rollback()
 		return in, false
-	} else {
-		return in, true
 	}
+	err = tx.Commit().Error
+	if err == nil {
+		checkReorder(in.ProductID)
+	}
+	return in, err == nil
 }
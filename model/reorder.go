@@ -0,0 +1,42 @@
+package model
+
+//Reorder represents a product with less stock on hand than its reorder
+//point, Suggested is the quantity to purchase: the reorder quantity, or
+//more if it doesn't cover the reorder point and the pending back-orders
+type Reorder struct {
+	Stock
+	MinStock        uint `json:"min_stock"`
+	ReorderQuantity uint `json:"reorder_quantity"`
+	BackOrdered     uint `json:"back_ordered"`
+	Suggested       uint `json:"suggested"`
+	//Providers that sold the product, cheapest first
+	Providers []ProductRankProviderPrice `json:"providers" gorm:"-"`
+}
+
+//TableName return the view of the products to reorder
+func (Reorder) TableName() string {
+	return "product_reorder"
+}
+
+//ReorderFields are the columns of reorder lists
+var ReorderFields = ListFields{
+	Key:    "product_id",
+	Amount: "suggested",
+	Columns: []string{"product_id", "name", "brand", "category", "on_hand",
+		"min_stock", "back_ordered", "suggested"},
+}
+
+//ReorderHook is called when the stock of a product goes below its
+//reorder point, it's called once until the stock is above it again
+var ReorderHook func(Reorder)
+
+//productReorderView is the SQL of the product_reorder view
+const productReorderView = "CREATE VIEW product_reorder AS " +
+	"SELECT product_stock.*, product.min_stock, product.reorder_quantity, " +
+	"COALESCE(back_order.pending, 0)::bigint AS back_ordered, " +
+	"GREATEST(product.reorder_quantity, product.min_stock - product_stock.on_hand + " +
+	"COALESCE(back_order.pending, 0))::bigint AS suggested " +
+	"FROM product_stock JOIN product ON product.id = product_stock.product_id " +
+	"LEFT JOIN (SELECT product_id, SUM(pending) AS pending FROM back_order " +
+	"GROUP BY product_id) AS back_order ON back_order.product_id = product_stock.product_id " +
+	"WHERE product.min_stock > 0 AND product_stock.on_hand < product.min_stock"
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+//GetReorders return a page of the products to reorder
+//with their suggested providers
+func GetReorders(in ListQuery) ([]Reorder, Page, error) {
+	var reorders []Reorder
+	page, err := list(&reorders, in, ReorderFields)
+	for i := range reorders {
+		if err != nil {
+			break
+		}
+		reorders[i].Providers, err = reorderProviders(reorders[i].ProductID)
+	}
+	return reorders, page, err
+}
+
+//reorderProviders return the providers that sold a product with their
+//lowest price, see GetRankProductPP, cheapest first
+func reorderProviders(product_id uint) ([]ProductRankProviderPrice, error) {
+	prices, err := GetRankProductPP(strconv.FormatUint(uint64(product_id), 10),
+		Date{End: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(prices, func(i, j int) bool { return prices[i].Price < prices[j].Price })
+	seen := map[string]bool{}
+	providers := []ProductRankProviderPrice{}
+	for _, price := range prices {
+		if !seen[price.Name+price.Mail] {
+			seen[price.Name+price.Mail] = true
+			providers = append(providers, price)
+		}
+	}
+	return providers, nil
+}
+
+//checkReorder calls ReorderHook for the products that went below their
+//reorder point, it's called after their stock or reorder point changes
+func checkReorder(product_ids ...uint) {
+	if len(product_ids) == 0 {
+		return
+	}
+	//Products above their reorder point are alerted again next time
+	err := dbmap.Exec("UPDATE product SET reorder_alerted = false WHERE reorder_alerted "+
+		"AND id IN (?) AND id NOT IN (SELECT product_id FROM product_reorder)",
+		product_ids).Error
+	checkErr(err, updateFailed)
+	var reorders []Reorder
+	err = dbmap.Where("product_id IN (?)", product_ids).Find(&reorders).Error
+	if err != nil {
+		checkErr(err, selectFailed)
+		return
+	}
+	for _, reorder := range reorders {
+		res := dbmap.Exec("UPDATE product SET reorder_alerted = true "+
+			"WHERE id = ? AND NOT reorder_alerted", reorder.ProductID)
+		if res.Error != nil || res.RowsAffected != 1 || ReorderHook == nil {
+			continue
+		}
+		reorder.Providers, err = reorderProviders(reorder.ProductID)
+		checkErr(err, selectFailed)
+		go ReorderHook(reorder)
+	}
+}
@@ -1,13 +1,129 @@
 package model
 
-import "time"
-import "github.com/jinzhu/gorm"
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+//Percent of tax charged over the subtotal of sales
+var salesTax = loadSalesTax(os.Getenv("SALES_TAX"))
 
 type Sale struct {
 	gorm.Model
-	CustomerID string    `json:"id_customer" binding:"required"`
-	UserID     string    `json:"id_user" binding:"required"`
+	CustomerID string    `json:"id_customer" binding:"required,rut"`
+	UserID     string    `json:"id_user" binding:"required,email"`
 	Date       time.Time `json:"date" binding:"required"`
+	//Warehouse where it's shipped from, the default one if it's zero
+	WarehouseID uint `json:"warehouse_id"`
+}
+
+//SaleSummary represents a sale with its amounts,
+//it's read from the sale_summary view
+type SaleSummary struct {
+	Sale
+	Subtotal uint `json:"subtotal"`
+	Tax      uint `json:"tax"`
+	Total    uint `json:"total"`
+}
+
+//TableName return the view of sale summaries
+func (SaleSummary) TableName() string {
+	return "sale_summary"
+}
+
+//SaleFields are the columns of sales lists
+var SaleFields = ListFields{
+	Key:     "id",
+	Date:    "date",
+	Amount:  "total",
+	Columns: []string{"id", "customer_id", "user_id", "warehouse_id", "date", "subtotal", "total", "created_at"},
+}
+
+//SaleDoc represents a sale with its line items,
+//Quantity and amounts are computed from the line items
+type SaleDoc struct {
+	Sale
+	Details    []SaleLine  `json:"details" binding:"required,min=1,unique=product_id,dive"`
+	BackOrders []BackOrder `json:"back_orders"`
+	Quantity   uint        `json:"quantity"`
+	Subtotal   uint        `json:"subtotal"`
+	Tax        uint        `json:"tax"`
+	Total      uint        `json:"total"`
+}
+
+//SaleLine represents a line item of a sale document,
+//the sale is set when it's inserted
+type SaleLine struct {
+	SaleID      uint   `json:"sale_id"`
+	ProductID   uint   `json:"product_id" binding:"required"`
+	Price       uint   `json:"price" binding:"gt=0"`
+	Quantity    uint   `json:"quantity" binding:"gt=0"`
+	ProductName string `json:"product_name"`
+	Brand       string `json:"brand"`
+	Category    string `json:"category"`
+	Total       uint   `json:"total"`
+}
+
+//Detail return the sale_detail row of the line item
+func (l SaleLine) Detail() SaleDetail {
+	return SaleDetail{
+		SaleID:    l.SaleID,
+		ProductID: l.ProductID,
+		Price:     l.Price,
+		Quantity:  l.Quantity,
+	}
+}
+
+//Sum computes the totals of the line items and the amounts of the sale
+func (s *SaleDoc) Sum() {
+	s.Quantity, s.Subtotal = 0, 0
+	for i := range s.Details {
+		line := &s.Details[i]
+		line.Total = line.Price * line.Quantity
+		s.Quantity += line.Quantity
+		s.Subtotal += line.Total
+	}
+	s.Tax = (s.Subtotal*salesTax + 50) / 100
+	s.Total = s.Subtotal + s.Tax
+}
+
+//productIDs return the products of the line items
+func (s *SaleDoc) productIDs() []uint {
+	products := make([]uint, len(s.Details))
+	for i, line := range s.Details {
+		products[i] = line.ProductID
+	}
+	return products
+}
+
+//saleSummaryView return the SQL of the sale_summary view,
+//amounts are computed like SaleDoc.Sum
+func saleSummaryView() string {
+	subtotal := "COALESCE(SUM(sale_detail.price * sale_detail.quantity), 0)::bigint"
+	tax := fmt.Sprintf("(%s * %d + 50) / 100", subtotal, salesTax)
+	return "CREATE VIEW sale_summary AS SELECT sale.*, " +
+		subtotal + " AS subtotal, " +
+		tax + " AS tax, " +
+		subtotal + " + " + tax + " AS total " +
+		"FROM sale LEFT JOIN sale_detail ON sale_detail.sale_id = sale.id " +
+		"GROUP BY sale.id"
+}
+
+//loadSalesTax return the tax percent, 19 by default
+func loadSalesTax(value string) uint {
+	if value == "" {
+		return 19
+	}
+	tax, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		log.Fatalln("SALES_TAX must be a percent:", err)
+	}
+	return uint(tax)
 }
 
 type InfoDashboard struct {
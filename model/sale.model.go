@@ -1,22 +1,73 @@
 package model
 
-//GetSale insert a sale in database
-func GetSale(customer_id, user_id string) (Sale, error) {
-	var sale Sale
-	sale.CustomerID = customer_id
-	sale.UserID = user_id
-	err := dbmap.Where("customer_id = $1 AND user_id = $2",
-		sale.CustomerID, sale.UserID).First(&sale).Error
-	checkErr(err, selectOneFailed)
-	return sale, err
+//GetSaleSummaries return a page of sales with their amounts
+func GetSaleSummaries(in ListQuery) ([]SaleSummary, Page, error) {
+	normalRutFilter(in, "customer_id")
+	var sales []SaleSummary
+	page, err := list(&sales, in, SaleFields)
+	return sales, page, err
 }
 
-//InsertSale insert a sale in database
-func InsertSale(in *Sale) (*Sale, bool) {
-	err = dbmap.Create(in).Error
+//GetSale return a sale with its line items for its id
+func GetSale(id uint) (SaleDoc, error) {
+	var doc SaleDoc
+	err := dbmap.First(&doc.Sale, id).Error
 	if err != nil {
-		return in, false
-	} else {
-		return in, true
+		checkErr(err, selectOneFailed)
+		return doc, err
 	}
+	err = dbmap.Table("sale_detail").
+		Select("sale_detail.*, product.name AS product_name, product.brand, product.category").
+		Joins("JOIN product ON product.id = sale_detail.product_id").
+		Where("sale_detail.sale_id = ?", id).
+		Order("sale_detail.product_id").
+		Scan(&doc.Details).Error
+	checkErr(err, selectFailed)
+	if err == nil {
+		doc.BackOrders, err = getSaleBackOrders(id)
+	}
+	doc.Sum()
+	return doc, err
+}
+
+//InsertSaleDoc insert a sale and its line items in one transaction,
+//nothing is inserted if any of them fails or the warehouse doesn't
+//exist. The stock of the lines is
+//moved following the stock policy, see sellStock, and the products
+//that go below their reorder point are notified
+func InsertSaleDoc(in *SaleDoc) error {
+	in.CustomerID = normalRut(in.CustomerID)
+	in.BackOrders = nil
+	tx := dbmap.Begin()
+	var err error
+	in.WarehouseID, err = checkWarehouse(tx, in.WarehouseID)
+	if err == nil {
+		err = tx.Create(&in.Sale).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range in.Details {
+		in.Details[i].SaleID = in.ID
+		detail := in.Details[i].Detail()
+		err = tx.Create(&detail).Error
+		var backOrder *BackOrder
+		if err == nil {
+			backOrder, err = sellStock(tx, in.Sale, detail)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if backOrder != nil {
+			in.BackOrders = append(in.BackOrders, *backOrder)
+		}
+	}
+	in.Sum()
+	err = tx.Commit().Error
+	if err == nil {
+		checkReorder(in.productIDs()...)
+	}
+	return err
 }
@@ -5,7 +5,8 @@ func GetSalesID(mail string, in Date) (TotalSales, error) {
 	var res TotalSales
 	err = dbmap.Raw("SELECT count(sale.user_id), sum(sale_detail.price*"+
 		"sale_detail.quantity) FROM sale, sale_detail WHERE sale.user_id=? "+
-		"AND sale.date>=? AND sale.date<=? AND sale_detail.sale_id=sale.id",
+		"AND sale.date>=? AND sale.date<=? AND sale_detail.sale_id=sale.id"+
+		in.warehouse("sale"),
 		mail, in.Start, in.End).Scan(&res).Error
 	return res, err
 }
@@ -15,7 +16,8 @@ func GetSales(in Date) (TotalSales, error) {
 	var res TotalSales
 	err = dbmap.Raw("SELECT count(*), sum(sale_detail.price*sale_detail.quantity)"+
 		" FROM sale, sale_detail WHERE sale.date>=? AND sale.date<=? "+
-		"AND sale_detail.sale_id=sale.id", in.Start, in.End).Scan(&res).Error
+		"AND sale_detail.sale_id=sale.id"+in.warehouse("sale"),
+		in.Start, in.End).Scan(&res).Error
 	return res, err
 }
 
@@ -24,7 +26,8 @@ func GetSalesProduct(id string, in Date) ([]SaleProductPrice, error) {
 	var res []SaleProductPrice
 	err = dbmap.Raw(" SELECT sale_detail.price, sale.date FROM sale_detail,"+
 		" sale WHERE sale.date >= ? AND sale.date <= ? AND sale_detail.sale_id"+
-		"=sale.id AND sale_detail.product_id= ? GROUP BY sale.date, "+
+		"=sale.id AND sale_detail.product_id= ?"+in.warehouse("sale")+
+		" GROUP BY sale.date, "+
 		"sale_detail.price ORDER BY sale.date",
 		in.Start, in.End, id).Scan(&res).Error
 	return res, err
@@ -10,11 +10,25 @@ func GetSaleDetail(purchase_id, product_id uint) (SaleDetail, error) {
 	return sale_detail, err
 }
 
-func InsertSaleDetail(in *SaleDetail) (*SaleDetail, bool) {
-	err = dbmap.Create(in).Error
+//InsertSaleDetail insert a sale_detail in database, its stock
+//is moved following the stock policy, see sellStock
+func InsertSaleDetail(in *SaleDetail) (*SaleDetail, error) {
+	tx := dbmap.Begin()
+	var sale Sale
+	err := tx.First(&sale, in.SaleID).Error
+	if err == nil {
+		err = tx.Create(in).Error
+	}
+	if err == nil {
+		_, err = sellStock(tx, sale, *in)
+	}
 	if err != nil {
-		return in, false
-	} else {
-		return in, true
+		tx.Rollback()
+		return in, err
+	}
+	err = tx.Commit().Error
+	if err == nil {
+		checkReorder(in.ProductID)
 	}
+	return in, err
 }
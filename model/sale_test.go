@@ -0,0 +1,49 @@
+package model
+
+import "testing"
+
+func TestSaleDocSum(t *testing.T) {
+	defer func(tax uint) { salesTax = tax }(salesTax)
+	tests := []struct {
+		name     string
+		tax      uint
+		lines    []SaleLine
+		totals   []uint
+		quantity uint
+		subtotal uint
+		taxes    uint
+		total    uint
+	}{
+		{"no lines", 19, nil, nil, 0, 0, 0, 0},
+		{"exact tax", 19, []SaleLine{{Price: 500, Quantity: 2}},
+			[]uint{1000}, 2, 1000, 190, 1190},
+		{"several lines", 19, []SaleLine{{Price: 100, Quantity: 1}, {Price: 25, Quantity: 6}},
+			[]uint{100, 150}, 7, 250, 48, 298},
+		{"rounds half up", 19, []SaleLine{{Price: 150, Quantity: 1}},
+			[]uint{150}, 1, 150, 29, 179},
+		{"rounds down", 10, []SaleLine{{Price: 7, Quantity: 2}},
+			[]uint{14}, 2, 14, 1, 15},
+		{"rounds to zero", 19, []SaleLine{{Price: 2, Quantity: 1}},
+			[]uint{2}, 1, 2, 0, 2},
+		{"without tax", 0, []SaleLine{{Price: 990, Quantity: 3}},
+			[]uint{2970}, 3, 2970, 0, 2970},
+	}
+	for _, test := range tests {
+		salesTax = test.tax
+		doc := SaleDoc{Details: test.lines}
+		//Amounts of a previous sum are computed again
+		doc.Quantity, doc.Subtotal = 99, 99
+		doc.Sum()
+		for i, line := range doc.Details {
+			if line.Total != test.totals[i] {
+				t.Errorf("%s: line %d total = %d, want %d", test.name, i, line.Total, test.totals[i])
+			}
+		}
+		if doc.Quantity != test.quantity || doc.Subtotal != test.subtotal ||
+			doc.Tax != test.taxes || doc.Total != test.total {
+			t.Errorf("%s: Sum = %d, %d, %d, %d, want %d, %d, %d, %d", test.name,
+				doc.Quantity, doc.Subtotal, doc.Tax, doc.Total,
+				test.quantity, test.subtotal, test.taxes, test.total)
+		}
+	}
+}
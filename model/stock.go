@@ -0,0 +1,115 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+//Policies applied when a sale line exceeds the stock on hand
+const (
+	StockReject    = "reject"
+	StockNegative  = "negative"
+	StockBackOrder = "backorder"
+)
+
+//Policy of sales that exceed the stock on hand
+var stockPolicy = loadStockPolicy(os.Getenv("STOCK_POLICY"))
+
+//Kinds of stock movements
+const (
+	MovePurchase   = "purchase"
+	MoveSale       = "sale"
+	MoveAdjustment = "adjustment"
+	MoveReturn     = "return"
+)
+
+//StockMovement represents an entry of the stock ledger, Quantity is
+//positive when stock comes in and negative when it goes out.
+//DocumentID is the purchase, sale, stock count or transfer of the movement, if any
+type StockMovement struct {
+	ID          uint      `json:"id" gorm:"primary_key"`
+	ProductID   uint      `json:"product_id" gorm:"index"`
+	WarehouseID uint      `json:"warehouse_id" gorm:"index"`
+	Kind        string    `json:"kind" gorm:"index"`
+	Quantity    int       `json:"quantity"`
+	DocumentID  uint      `json:"document_id"`
+	Reason      string    `json:"reason"`
+	UserID      string    `json:"user_id"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
+
+//StockMovementFields are the columns of stock movements lists
+var StockMovementFields = ListFields{
+	Key:     "id",
+	Date:    "created_at",
+	Columns: []string{"id", "product_id", "warehouse_id", "kind", "document_id", "user_id", "created_at"},
+}
+
+//Represents a movement made by hand, adjustments can be negative
+//but returns of sold products only come in. It's made in the
+//default warehouse if WarehouseID is zero
+type StockMovementInput struct {
+	ProductID   uint   `json:"product_id" binding:"required"`
+	WarehouseID uint   `json:"warehouse_id"`
+	Kind        string `json:"kind" binding:"required,oneof=adjustment return"`
+	Quantity    int    `json:"quantity" binding:"required"`
+	DocumentID  uint   `json:"document_id"`
+	Reason      string `json:"reason" binding:"required"`
+}
+
+//Stock represents the quantity on hand of a product,
+//it's read from the product_stock view
+type Stock struct {
+	ProductID uint   `json:"product_id"`
+	Name      string `json:"name"`
+	Brand     string `json:"brand"`
+	Category  string `json:"category"`
+	OnHand    int    `json:"on_hand"`
+}
+
+//TableName return the view of the stock of products
+func (Stock) TableName() string {
+	return "product_stock"
+}
+
+//StockFields are the columns of stock lists
+var StockFields = ListFields{
+	Key:     "product_id",
+	Amount:  "on_hand",
+	Columns: []string{"product_id", "name", "brand", "category", "on_hand"},
+}
+
+//StockError is returned when a sale line exceeds the stock on hand and
+//the policy is to reject it, or when a transfer line exceeds it
+type StockError struct {
+	ProductID uint
+	OnHand    int
+	Quantity  uint
+}
+
+func (e StockError) Error() string {
+	return fmt.Sprintf("The product %d has %d on hand, %d were requested",
+		e.ProductID, e.OnHand, e.Quantity)
+}
+
+//loadStockPolicy return the stock policy, negative by default
+//so sales are accepted as they were before the stock ledger
+func loadStockPolicy(value string) string {
+	switch value {
+	case "":
+		return StockNegative
+	case StockReject, StockNegative, StockBackOrder:
+		return value
+	}
+	log.Fatalln("STOCK_POLICY must be reject, negative or backorder")
+	return ""
+}
+
+//productStockView is the SQL of the product_stock view
+const productStockView = "CREATE VIEW product_stock AS " +
+	"SELECT product.id AS product_id, product.name, product.brand, product.category, " +
+	"COALESCE(SUM(stock_movement.quantity), 0)::bigint AS on_hand " +
+	"FROM product LEFT JOIN stock_movement ON stock_movement.product_id = product.id " +
+	"WHERE product.deleted_at IS NULL GROUP BY product.id"
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"strconv"
+
+	"github.com/jinzhu/gorm"
+)
+
+//GetStocks return a page of the stock of products
+func GetStocks(in ListQuery) ([]Stock, Page, error) {
+	var stocks []Stock
+	page, err := list(&stocks, in, StockFields)
+	return stocks, page, err
+}
+
+//GetStock return the stock of a product
+func GetStock(product_id uint) (Stock, error) {
+	var stock Stock
+	err := dbmap.Where("product_id = ?", product_id).First(&stock).Error
+	checkErr(err, selectOneFailed)
+	return stock, err
+}
+
+//GetStockMovements return a page of the stock movements of a product
+func GetStockMovements(product_id uint, in ListQuery) ([]StockMovement, Page, error) {
+	var movements []StockMovement
+	in.Filters["product_id"] = strconv.FormatUint(uint64(product_id), 10)
+	page, err := list(&movements, in, StockMovementFields)
+	return movements, page, err
+}
+
+//InsertStockMovement insert a movement made by hand
+func InsertStockMovement(in StockMovementInput, user_id string) (StockMovement, error) {
+	movement := StockMovement{
+		ProductID:  in.ProductID,
+		Kind:       in.Kind,
+		Quantity:   in.Quantity,
+		DocumentID: in.DocumentID,
+		Reason:     in.Reason,
+		UserID:     user_id,
+	}
+	var count int
+	err := dbmap.Model(&Product{}).Where("id = ?", in.ProductID).Count(&count).Error
+	if err == nil && count == 0 {
+		err = ErrMissing
+	}
+	if err == nil {
+		movement.WarehouseID, err = checkWarehouse(dbmap, in.WarehouseID)
+	}
+	if err != nil {
+		return movement, err
+	}
+	err = dbmap.Create(&movement).Error
+	if err == nil {
+		checkReorder(movement.ProductID)
+	}
+	return movement, err
+}
+
+//moveStock insert the movement of a purchase, sale or transfer line in the transaction
+func moveStock(tx *gorm.DB, kind string, document_id, product_id, warehouse_id uint, quantity int) error {
+	return tx.Create(&StockMovement{
+		ProductID:   product_id,
+		WarehouseID: warehouse_id,
+		Kind:        kind,
+		Quantity:    quantity,
+		DocumentID:  document_id,
+	}).Error
+}
+
+//lockStock locks the stock of a product until the transaction
+//ends and return its quantity on hand in a warehouse
+func lockStock(tx *gorm.DB, product_id, warehouse_id uint) (int, error) {
+	err := tx.Exec("SELECT id FROM product WHERE id = ? FOR UPDATE", product_id).Error
+	if err != nil {
+		return 0, err
+	}
+	var stock struct {
+		OnHand int
+	}
+	err = tx.Raw("SELECT COALESCE(SUM(quantity), 0) AS on_hand "+
+		"FROM stock_movement WHERE product_id = ? AND warehouse_id = ?",
+		product_id, warehouse_id).Scan(&stock).Error
+	return stock.OnHand, err
+}
+
+//sellStock moves the stock of a sale line following the stock policy,
+//it return the back-order of the quantity that isn't on hand in the
+//warehouse of the sale, if any
+func sellStock(tx *gorm.DB, sale Sale, detail SaleDetail) (*BackOrder, error) {
+	onHand, err := lockStock(tx, detail.ProductID, sale.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	quantity := int(detail.Quantity)
+	shipped, ok := shipQuantity(stockPolicy, onHand, quantity)
+	if !ok {
+		return nil, StockError{detail.ProductID, onHand, detail.Quantity}
+	}
+	if shipped > 0 {
+		err = moveStock(tx, MoveSale, sale.ID, detail.ProductID, sale.WarehouseID, -shipped)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if shipped == quantity {
+		return nil, nil
+	}
+	backOrder := BackOrder{
+		SaleID:      sale.ID,
+		ProductID:   detail.ProductID,
+		WarehouseID: sale.WarehouseID,
+		CustomerID:  sale.CustomerID,
+		UserID:      sale.UserID,
+		Quantity:    uint(quantity - shipped),
+		Pending:     uint(quantity - shipped),
+	}
+	err = tx.Create(&backOrder).Error
+	return &backOrder, err
+}
+
+//shipQuantity return the quantity of a sale line shipped with the
+//stock on hand following the policy, and false if the policy rejects it.
+//The rest of the quantity is back-ordered
+func shipQuantity(policy string, onHand, quantity int) (int, bool) {
+	if quantity <= onHand {
+		return quantity, true
+	}
+	switch policy {
+	case StockReject:
+		return 0, false
+	case StockBackOrder:
+		if onHand < 0 {
+			return 0, true
+		}
+		return onHand, true
+	}
+	return quantity, true
+}
+
+//backfillStock inserts the movements of purchase and sale lines saved
+//before the stock ledger existed. Back-ordered sale lines are skipped,
+//their movements are written when they're shipped
+func backfillStock(db *gorm.DB) {
+	db.Exec("INSERT INTO stock_movement " +
+		"(product_id, warehouse_id, kind, quantity, document_id, reason, user_id, created_at) " +
+		"SELECT purchase_detail.product_id, purchase.warehouse_id, '" + MovePurchase + "', " +
+		"purchase_detail.quantity, purchase.id, '', '', purchase.date " +
+		"FROM purchase_detail JOIN purchase ON purchase.id = purchase_detail.purchase_id " +
+		"WHERE NOT EXISTS (SELECT 1 FROM stock_movement WHERE kind = '" + MovePurchase + "' " +
+		"AND document_id = purchase.id AND product_id = purchase_detail.product_id)")
+	db.Exec("INSERT INTO stock_movement " +
+		"(product_id, warehouse_id, kind, quantity, document_id, reason, user_id, created_at) " +
+		"SELECT sale_detail.product_id, sale.warehouse_id, '" + MoveSale + "', " +
+		"-sale_detail.quantity, sale.id, '', '', sale.date " +
+		"FROM sale_detail JOIN sale ON sale.id = sale_detail.sale_id " +
+		"WHERE NOT EXISTS (SELECT 1 FROM stock_movement WHERE kind = '" + MoveSale + "' " +
+		"AND document_id = sale.id AND product_id = sale_detail.product_id) " +
+		"AND NOT EXISTS (SELECT 1 FROM back_order WHERE back_order.sale_id = sale.id " +
+		"AND back_order.product_id = sale_detail.product_id)")
+}
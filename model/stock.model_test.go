@@ -0,0 +1,46 @@
+package model
+
+import "testing"
+
+func TestShipQuantity(t *testing.T) {
+	tests := []struct {
+		policy   string
+		onHand   int
+		quantity int
+		shipped  int
+		ok       bool
+	}{
+		{StockReject, 10, 4, 4, true},
+		{StockReject, 4, 4, 4, true},
+		{StockReject, 3, 4, 0, false},
+		{StockReject, -2, 1, 0, false},
+		{StockNegative, 10, 4, 4, true},
+		{StockNegative, 3, 4, 4, true},
+		{StockNegative, -2, 1, 1, true},
+		{StockBackOrder, 10, 4, 4, true},
+		{StockBackOrder, 3, 4, 3, true},
+		{StockBackOrder, 0, 4, 0, true},
+		{StockBackOrder, -2, 4, 0, true},
+	}
+	for _, test := range tests {
+		shipped, ok := shipQuantity(test.policy, test.onHand, test.quantity)
+		if shipped != test.shipped || ok != test.ok {
+			t.Errorf("shipQuantity(%s, %d, %d) = %d, %v, want %d, %v",
+				test.policy, test.onHand, test.quantity, shipped, ok, test.shipped, test.ok)
+		}
+	}
+}
+
+func TestLoadStockPolicy(t *testing.T) {
+	tests := map[string]string{
+		"":             StockNegative,
+		StockReject:    StockReject,
+		StockNegative:  StockNegative,
+		StockBackOrder: StockBackOrder,
+	}
+	for value, want := range tests {
+		if policy := loadStockPolicy(value); policy != want {
+			t.Errorf("loadStockPolicy(%q) = %q, want %q", value, policy, want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package model
+
+import "time"
+
+//Status of stock counts
+const (
+	CountOpen   = "open"
+	CountClosed = "closed"
+)
+
+//StockCount represents a physical count of the stock of a warehouse, the
+//default one if WarehouseID is zero. Its lines are recorded while it's
+//open and closing it adjusts the stock
+type StockCount struct {
+	ID          uint       `json:"id" gorm:"primary_key"`
+	Name        string     `json:"name" binding:"required"`
+	WarehouseID uint       `json:"warehouse_id"`
+	Status      string     `json:"status" gorm:"index"`
+	OpenedBy    string     `json:"opened_by"`
+	ClosedBy    string     `json:"closed_by"`
+	ClosedAt    *time.Time `json:"closed_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//StockCountFields are the columns of stock counts lists
+var StockCountFields = ListFields{
+	Key:     "id",
+	Date:    "created_at",
+	Columns: []string{"id", "name", "warehouse_id", "status", "opened_by", "closed_by", "created_at"},
+}
+
+//StockCountLine represents the counted quantity of a product,
+//Expected is the stock on hand when it was counted
+type StockCountLine struct {
+	StockCountID uint      `json:"stock_count_id" gorm:"primary_key"`
+	ProductID    uint      `json:"product_id" gorm:"primary_key"`
+	Counted      int       `json:"counted"`
+	Expected     int       `json:"expected"`
+	Reason       string    `json:"reason"`
+	UserID       string    `json:"user_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+//StockCountDoc represents a stock count with its lines
+type StockCountDoc struct {
+	StockCount
+	Lines []StockCountLine `json:"lines"`
+}
+
+//Represents the counted quantities recorded in a stock count,
+//products counted again replace their line
+type StockCountInput struct {
+	Lines []StockCountLineInput `json:"lines" binding:"required,min=1,unique=product_id,dive"`
+}
+
+//Represents a counted quantity, the reason code of its
+//adjustment is "count" if it's empty
+type StockCountLineInput struct {
+	ProductID uint   `json:"product_id" binding:"required"`
+	Counted   *int   `json:"counted" binding:"exists,min=0"`
+	Reason    string `json:"reason" binding:"omitempty,oneof=count damaged expired lost found"`
+}
+
+//Default reason code of stock count adjustments
+const reasonCount = "count"
+
+//StockCountVariance represents the difference between the counted and the
+//expected quantity of a product, valued at its last purchase price
+type StockCountVariance struct {
+	ProductID  uint   `json:"product_id"`
+	Name       string `json:"name"`
+	Expected   int    `json:"expected"`
+	Counted    int    `json:"counted"`
+	Difference int    `json:"difference"`
+	Reason     string `json:"reason"`
+	Cost       uint   `json:"cost"`
+	Value      int    `json:"value"`
+}
+
+//StockCountReport represents the variances of a stock count,
+//Loss and Gain are the values of the negative and positive ones
+type StockCountReport struct {
+	StockCount
+	Lines []StockCountVariance `json:"lines"`
+	Loss  int                  `json:"loss"`
+	Gain  int                  `json:"gain"`
+	Value int                  `json:"value"`
+}
+
+//Sum computes the values of the lines and the report
+func (r *StockCountReport) Sum() {
+	r.Loss, r.Gain = 0, 0
+	for i := range r.Lines {
+		line := &r.Lines[i]
+		line.Difference = line.Counted - line.Expected
+		line.Value = line.Difference * int(line.Cost)
+		if line.Value < 0 {
+			r.Loss += line.Value
+		} else {
+			r.Gain += line.Value
+		}
+	}
+	r.Value = r.Loss + r.Gain
+}
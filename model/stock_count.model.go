@@ -0,0 +1,186 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+//GetStockCounts return a page of stock counts
+func GetStockCounts(in ListQuery) ([]StockCount, Page, error) {
+	var counts []StockCount
+	page, err := list(&counts, in, StockCountFields)
+	return counts, page, err
+}
+
+//GetStockCount return a stock count with its lines
+func GetStockCount(id uint) (StockCountDoc, error) {
+	var doc StockCountDoc
+	err := dbmap.First(&doc.StockCount, id).Error
+	if err != nil {
+		checkErr(err, selectOneFailed)
+		return doc, err
+	}
+	err = dbmap.Where("stock_count_id = ?", id).Order("product_id").Find(&doc.Lines).Error
+	checkErr(err, selectFailed)
+	return doc, err
+}
+
+//InsertStockCount opens a stock count, it fails
+//with ErrMissing if the warehouse doesn't exist
+func InsertStockCount(in *StockCount, user_id string) error {
+	in.Status = CountOpen
+	in.OpenedBy = user_id
+	in.ClosedBy = ""
+	in.ClosedAt = nil
+	var err error
+	in.WarehouseID, err = checkWarehouse(dbmap, in.WarehouseID)
+	if err != nil {
+		return err
+	}
+	return dbmap.Create(in).Error
+}
+
+//InsertStockCountLines records counted quantities in an open stock count,
+//the expected quantity of each line is the stock on hand of its product.
+//It fails with ErrClosed if the count is closed
+func InsertStockCountLines(id uint, in StockCountInput, user_id string) (StockCountDoc, error) {
+	tx := dbmap.Begin()
+	count, err := lockStockCount(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return StockCountDoc{}, err
+	}
+	products := make([]uint, len(in.Lines))
+	for i, line := range in.Lines {
+		products[i] = line.ProductID
+	}
+	var found int
+	err = tx.Model(&Product{}).Where("id IN (?)", products).Count(&found).Error
+	if err == nil && found != len(products) {
+		err = ErrMissing
+	}
+	if err != nil {
+		tx.Rollback()
+		return StockCountDoc{}, err
+	}
+	for _, input := range in.Lines {
+		line := StockCountLine{
+			StockCountID: id,
+			ProductID:    input.ProductID,
+			Counted:      *input.Counted,
+			Reason:       input.Reason,
+			UserID:       user_id,
+		}
+		if line.Reason == "" {
+			line.Reason = reasonCount
+		}
+		line.Expected, err = lockStock(tx, line.ProductID, count.WarehouseID)
+		if err == nil {
+			err = tx.Where("stock_count_id = ? AND product_id = ?", id, line.ProductID).
+				Delete(StockCountLine{}).Error
+		}
+		if err == nil {
+			err = tx.Create(&line).Error
+		}
+		if err != nil {
+			tx.Rollback()
+			return StockCountDoc{}, err
+		}
+	}
+	err = tx.Commit().Error
+	if err != nil {
+		return StockCountDoc{}, err
+	}
+	return GetStockCount(id)
+}
+
+//CloseStockCount closes an open stock count, the lines which counted
+//quantity differs from the expected one are adjusted with their reason code
+func CloseStockCount(id uint, user_id string) (StockCountDoc, error) {
+	tx := dbmap.Begin()
+	count, err := lockStockCount(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return StockCountDoc{}, err
+	}
+	var lines []StockCountLine
+	err = tx.Where("stock_count_id = ?", id).Find(&lines).Error
+	if err != nil {
+		tx.Rollback()
+		return StockCountDoc{}, err
+	}
+	var products []uint
+	for _, line := range lines {
+		if line.Counted == line.Expected {
+			continue
+		}
+		err = tx.Create(&StockMovement{
+			ProductID:   line.ProductID,
+			WarehouseID: count.WarehouseID,
+			Kind:        MoveAdjustment,
+			Quantity:    line.Counted - line.Expected,
+			DocumentID:  id,
+			Reason:      line.Reason,
+			UserID:      user_id,
+		}).Error
+		if err != nil {
+			tx.Rollback()
+			return StockCountDoc{}, err
+		}
+		products = append(products, line.ProductID)
+	}
+	err = tx.Model(&StockCount{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":    CountClosed,
+		"closed_by": user_id,
+		"closed_at": time.Now(),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return StockCountDoc{}, err
+	}
+	err = tx.Commit().Error
+	if err != nil {
+		return StockCountDoc{}, err
+	}
+	checkReorder(products...)
+	return GetStockCount(id)
+}
+
+//GetStockCountReport return the variances of a stock count,
+//valued at the last purchase price of each product
+func GetStockCountReport(id uint) (StockCountReport, error) {
+	var report StockCountReport
+	err := dbmap.First(&report.StockCount, id).Error
+	if err != nil {
+		checkErr(err, selectOneFailed)
+		return report, err
+	}
+	err = dbmap.Table("stock_count_line").
+		Select("stock_count_line.product_id, product.name, stock_count_line.expected, "+
+			"stock_count_line.counted, stock_count_line.reason, "+
+			"COALESCE(last_price.price, 0) AS cost").
+		Joins("JOIN product ON product.id = stock_count_line.product_id").
+		Joins("LEFT JOIN (SELECT DISTINCT ON (purchase_detail.product_id) "+
+			"purchase_detail.product_id, purchase_detail.price FROM purchase_detail "+
+			"JOIN purchase ON purchase.id = purchase_detail.purchase_id "+
+			"ORDER BY purchase_detail.product_id, purchase.date DESC, purchase.id DESC) "+
+			"AS last_price ON last_price.product_id = stock_count_line.product_id").
+		Where("stock_count_line.stock_count_id = ?", id).
+		Order("stock_count_line.product_id").
+		Scan(&report.Lines).Error
+	checkErr(err, selectFailed)
+	report.Sum()
+	return report, err
+}
+
+//lockStockCount locks an open stock count until the transaction ends
+//and return it, it fails with ErrClosed if the count is closed
+func lockStockCount(tx *gorm.DB, id uint) (StockCount, error) {
+	var count StockCount
+	err := tx.Set("gorm:query_option", "FOR UPDATE").First(&count, id).Error
+	if err == nil && count.Status != CountOpen {
+		err = ErrClosed
+	}
+	return count, err
+}
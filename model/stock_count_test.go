@@ -0,0 +1,45 @@
+package model
+
+import "testing"
+
+func TestStockCountReportSum(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []StockCountVariance
+		values []int
+		loss   int
+		gain   int
+		value  int
+	}{
+		{"no lines", nil, nil, 0, 0, 0},
+		{"as expected", []StockCountVariance{{Expected: 5, Counted: 5, Cost: 100}},
+			[]int{0}, 0, 0, 0},
+		{"loss", []StockCountVariance{{Expected: 10, Counted: 7, Cost: 250}},
+			[]int{-750}, -750, 0, -750},
+		{"gain", []StockCountVariance{{Expected: 2, Counted: 6, Cost: 30}},
+			[]int{120}, 0, 120, 120},
+		{"negative stock", []StockCountVariance{{Expected: -3, Counted: 0, Cost: 10}},
+			[]int{30}, 0, 30, 30},
+		{"without cost", []StockCountVariance{{Expected: 4, Counted: 1}},
+			[]int{0}, 0, 0, 0},
+		{"loss and gain", []StockCountVariance{
+			{Expected: 10, Counted: 8, Cost: 100},
+			{Expected: 1, Counted: 4, Cost: 50},
+			{Expected: 6, Counted: 5, Cost: 20},
+		}, []int{-200, 150, -20}, -220, 150, -70},
+	}
+	for _, test := range tests {
+		report := StockCountReport{Lines: test.lines, Loss: 1, Gain: 1}
+		report.Sum()
+		for i, line := range report.Lines {
+			if line.Difference != line.Counted-line.Expected || line.Value != test.values[i] {
+				t.Errorf("%s: line %d = %d, %d, want %d, %d", test.name, i,
+					line.Difference, line.Value, line.Counted-line.Expected, test.values[i])
+			}
+		}
+		if report.Loss != test.loss || report.Gain != test.gain || report.Value != test.value {
+			t.Errorf("%s: Sum = %d, %d, %d, want %d, %d, %d", test.name,
+				report.Loss, report.Gain, report.Value, test.loss, test.gain, test.value)
+		}
+	}
+}
@@ -7,3 +7,9 @@ type Tag struct {
 	gorm.Model
 	Name string `json:"name" binding:"required"`
 }
+
+//TagFields are the columns of tags lists
+var TagFields = ListFields{
+	Key:     "id",
+	Columns: []string{"id", "name", "created_at", "updated_at"},
+}
@@ -1,5 +1,14 @@
 package model
 
+import "github.com/jinzhu/gorm"
+
+//This function allow obtain a page of tags' resource.
+func GetTags(in ListQuery) ([]Tag, Page, error) {
+	var tags []Tag
+	page, err := list(&tags, in, TagFields)
+	return tags, page, err
+}
+
 //This function allow insert tag' resource
 func InsertTag(in *Tag) (*Tag, bool) {
 	err = dbmap.Create(in).Error
@@ -18,3 +27,73 @@ func GetTag(id uint) (Tag, error) {
 	checkErr(err, selectOneFailed)
 	return tag, err
 }
+
+//GetAnyTag return a tag with an ID, soft deleted ones included
+func GetAnyTag(id uint) (Tag, error) {
+	var tag Tag
+	err := dbmap.Unscoped().First(&tag, id).Error
+	checkErr(err, selectOneFailed)
+	return tag, err
+}
+
+//This function allow update tag' resource for his id.
+func UpdateTag(id uint, in Tag) (Tag, error) {
+	tag, err := GetTag(id)
+	if err != nil {
+		return tag, err
+	}
+	err = dbmap.Model(&tag).Updates(map[string]interface{}{
+		"name": in.Name,
+	}).Error
+	return tag, err
+}
+
+//This function allow soft delete tag' resource for his id.
+func DeleteTag(id uint) (Tag, error) {
+	tag, err := GetTag(id)
+	if err != nil {
+		return tag, err
+	}
+	err = dbmap.Delete(&tag).Error
+	return tag, err
+}
+
+//This function allow restore a soft deleted tag' resource.
+func RestoreTag(id uint) (Tag, error) {
+	res := dbmap.Unscoped().Model(&Tag{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		UpdateColumn("deleted_at", gorm.Expr("NULL"))
+	if res.Error != nil {
+		return Tag{}, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return Tag{}, ErrNotDeleted
+	}
+	return GetTag(id)
+}
+
+//This function allow delete permanently tag' resource,
+//it fails if customers reference it. Soft deleted
+//references are removed with the tag
+func HardDeleteTag(id uint) error {
+	var count int
+	err := dbmap.Model(&TagCustomer{}).Where("tag_id = ?", id).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrReferenced
+	}
+	tx := dbmap.Begin()
+	err = tx.Unscoped().Where("tag_id = ?", id).Delete(&TagCustomer{}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Unscoped().Where("id = ?", id).Delete(&Tag{}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
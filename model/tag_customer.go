@@ -4,8 +4,8 @@ import "time"
 
 //This struct represent customer tag in server
 type TagCustomer struct {
-	TagID      int    `json:"id_tag" gorm:"primary_key"`
-	CustomerID string `json:"id_customer" gorm:"primary_key"`
+	TagID      int    `json:"id_tag" binding:"gt=0" gorm:"primary_key"`
+	CustomerID string `json:"id_customer" binding:"required,rut" gorm:"primary_key"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
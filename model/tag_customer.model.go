@@ -2,6 +2,7 @@ package model
 
 //InsertTagCustomer insert tag of customer in database
 func InsertTagCustomer(in *TagCustomer) (*TagCustomer, bool) {
+	in.CustomerID = normalRut(in.CustomerID)
 	err = dbmap.Create(in).Error
 	if err != nil {
 		return in, false
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+//RefreshToken represents a refresh token issued to an account,
+//only the hash of the token is stored
+type RefreshToken struct {
+	ID        string `gorm:"primary_key;type:varchar(64)"`
+	UserID    string `gorm:"index"`
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+
+	CreatedAt time.Time
+}
+
+//RevokedToken represents an access token revoked before its expiration
+type RevokedToken struct {
+	ID        string `gorm:"primary_key;type:varchar(64)"`
+	ExpiresAt time.Time
+
+	CreatedAt time.Time
+}
+
+//ResetToken represents a single-use token to reset a password,
+//only the hash of the token is stored
+type ResetToken struct {
+	ID        string `gorm:"primary_key;type:varchar(64)"`
+	UserID    string `gorm:"index"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+
+	CreatedAt time.Time
+}
+
+//RecoveryCode represents a single-use code that replaces the second factor,
+//only the hash of the code is stored
+type RecoveryCode struct {
+	ID     string `gorm:"primary_key;type:varchar(64)"`
+	UserID string `gorm:"index"`
+	UsedAt *time.Time
+
+	CreatedAt time.Time
+}
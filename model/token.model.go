@@ -0,0 +1,84 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+//InsertRefreshToken insert a refresh token in database
+func InsertRefreshToken(in *RefreshToken) (*RefreshToken, error) {
+	err = dbmap.Create(in).Error
+	return in, err
+}
+
+//GetRefreshToken return a refresh token with its hash
+func GetRefreshToken(id string) (RefreshToken, error) {
+	var token RefreshToken
+	err := dbmap.Where("id = ?", id).First(&token).Error
+	checkErr(err, selectOneFailed)
+	return token, err
+}
+
+//RevokeRefreshToken revokes a refresh token, it returns false
+//if the token was already revoked
+func RevokeRefreshToken(id string) (bool, error) {
+	res := dbmap.Model(&RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return res.RowsAffected == 1, res.Error
+}
+
+//RevokeRefreshTokens revokes all refresh tokens of an account
+func RevokeRefreshTokens(mail string) error {
+	return dbmap.Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", mail).
+		Update("revoked_at", time.Now()).Error
+}
+
+//InsertRevokedToken revokes an access token,
+//already expired tokens are removed from the table
+func InsertRevokedToken(in *RevokedToken) (*RevokedToken, error) {
+	err = dbmap.Where("expires_at < ?", time.Now()).Delete(RevokedToken{}).Error
+	checkErr(err, deleteFailed)
+	err = dbmap.Create(in).Error
+	return in, err
+}
+
+//IsTokenRevoked return true if the access token was revoked
+func IsTokenRevoked(id string) bool {
+	var count int
+	err := dbmap.Model(&RevokedToken{}).Where("id = ?", id).Count(&count).Error
+	checkErr(err, countFailed)
+	return err != nil || count > 0
+}
+
+//InsertResetToken insert a reset token in database,
+//previous reset tokens of the account are invalidated
+func InsertResetToken(in *ResetToken) (*ResetToken, error) {
+	err = dbmap.Model(&ResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", in.UserID).
+		Update("used_at", time.Now()).Error
+	if err != nil {
+		return in, err
+	}
+	err = dbmap.Create(in).Error
+	return in, err
+}
+
+//UseResetToken marks a reset token as used and returns it,
+//it fails if the token doesn't exist, is expired or was already used
+func UseResetToken(id string) (ResetToken, error) {
+	var token ResetToken
+	res := dbmap.Model(&ResetToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, time.Now()).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return token, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return token, gorm.ErrRecordNotFound
+	}
+	err := dbmap.Where("id = ?", id).First(&token).Error
+	return token, err
+}
@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+//SetTOTPSecret stores a pending second factor secret of an account
+func SetTOTPSecret(mail, secret string) error {
+	return dbmap.Model(&UserAcc{}).Where("mail = ?", mail).
+		Updates(map[string]interface{}{
+			"totp_secret":    secret,
+			"totp_enabled":   false,
+			"totp_last_step": 0,
+		}).Error
+}
+
+//UseTOTPStep registers the time step of a used code, it returns false
+//if that step or a later one was already used
+func UseTOTPStep(mail string, step int64) (bool, error) {
+	res := dbmap.Model(&UserAcc{}).
+		Where("mail = ? AND totp_last_step < ?", mail, step).
+		UpdateColumn("totp_last_step", step)
+	return res.RowsAffected == 1, res.Error
+}
+
+//EnableTOTP enables the second factor of an account
+//and replaces its recovery codes
+func EnableTOTP(mail string, codes []string) error {
+	tx := dbmap.Begin()
+	err := tx.Model(&UserAcc{}).Where("mail = ?", mail).
+		UpdateColumn("totp_enabled", true).Error
+	if err == nil {
+		err = tx.Where("user_id = ?", mail).Delete(RecoveryCode{}).Error
+	}
+	for i := 0; err == nil && i < len(codes); i++ {
+		err = tx.Create(&RecoveryCode{ID: codes[i], UserID: mail}).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+//DisableTOTP removes the second factor and recovery codes of an account
+func DisableTOTP(mail string) error {
+	tx := dbmap.Begin()
+	err := tx.Model(&UserAcc{}).Where("mail = ?", mail).
+		Updates(map[string]interface{}{
+			"totp_secret":    "",
+			"totp_enabled":   false,
+			"totp_last_step": 0,
+		}).Error
+	if err == nil {
+		err = tx.Where("user_id = ?", mail).Delete(RecoveryCode{}).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+//UseRecoveryCode marks a recovery code of an account as used,
+//it returns false if the code doesn't exist or was already used
+func UseRecoveryCode(mail, id string) (bool, error) {
+	res := dbmap.Model(&RecoveryCode{}).
+		Where("id = ? AND user_id = ? AND used_at IS NULL", id, mail).
+		Update("used_at", time.Now())
+	return res.RowsAffected == 1, res.Error
+}
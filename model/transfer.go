@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+//Kind of the stock movements of transfers
+const MoveTransfer = "transfer"
+
+//Transfer represents stock moved from a warehouse to another
+type Transfer struct {
+	ID        uint      `json:"id" gorm:"primary_key"`
+	FromID    uint      `json:"from_id" binding:"required"`
+	ToID      uint      `json:"to_id" binding:"required,nefield=FromID"`
+	Date      time.Time `json:"date" binding:"required"`
+	Note      string    `json:"note"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+//TransferFields are the columns of transfers lists
+var TransferFields = ListFields{
+	Key:     "id",
+	Date:    "date",
+	Columns: []string{"id", "from_id", "to_id", "user_id", "date", "created_at"},
+}
+
+//TransferDetail represents the quantity of a product in a transfer
+type TransferDetail struct {
+	TransferID uint `json:"transfer_id" gorm:"primary_key"`
+	ProductID  uint `json:"product_id" gorm:"primary_key"`
+	Quantity   uint `json:"quantity"`
+}
+
+//TransferDoc represents a transfer with its line items,
+//Quantity is computed from the line items
+type TransferDoc struct {
+	Transfer
+	Details  []TransferLine `json:"details" binding:"required,min=1,unique=product_id,dive"`
+	Quantity uint           `json:"quantity"`
+}
+
+//TransferLine represents a line item of a transfer document,
+//the transfer is set when it's inserted
+type TransferLine struct {
+	TransferID  uint   `json:"transfer_id"`
+	ProductID   uint   `json:"product_id" binding:"required"`
+	Quantity    uint   `json:"quantity" binding:"gt=0"`
+	ProductName string `json:"product_name"`
+}
+
+//Detail return the transfer_detail row of the line item
+func (l TransferLine) Detail() TransferDetail {
+	return TransferDetail{
+		TransferID: l.TransferID,
+		ProductID:  l.ProductID,
+		Quantity:   l.Quantity,
+	}
+}
+
+//Sum computes the quantity of the transfer
+func (t *TransferDoc) Sum() {
+	t.Quantity = 0
+	for _, line := range t.Details {
+		t.Quantity += line.Quantity
+	}
+}
+
+//productIDs return the products of the line items
+func (t *TransferDoc) productIDs() []uint {
+	products := make([]uint, len(t.Details))
+	for i, line := range t.Details {
+		products[i] = line.ProductID
+	}
+	return products
+}
@@ -0,0 +1,88 @@
+package model
+
+//GetTransfers return a page of transfers
+func GetTransfers(in ListQuery) ([]Transfer, Page, error) {
+	var transfers []Transfer
+	page, err := list(&transfers, in, TransferFields)
+	return transfers, page, err
+}
+
+//GetTransfer return a transfer with its line items
+func GetTransfer(id uint) (TransferDoc, error) {
+	var doc TransferDoc
+	err := dbmap.First(&doc.Transfer, id).Error
+	if err != nil {
+		checkErr(err, selectOneFailed)
+		return doc, err
+	}
+	err = dbmap.Table("transfer_detail").
+		Select("transfer_detail.*, product.name AS product_name").
+		Joins("JOIN product ON product.id = transfer_detail.product_id").
+		Where("transfer_detail.transfer_id = ?", id).
+		Order("transfer_detail.product_id").
+		Scan(&doc.Details).Error
+	checkErr(err, selectFailed)
+	doc.Sum()
+	return doc, err
+}
+
+//InsertTransferDoc insert a transfer and its line items in one transaction,
+//nothing is inserted if a warehouse or a product doesn't exist, or if the
+//source warehouse hasn't the quantity on hand. The received products
+//fulfill their pending back-orders in the destination warehouse
+func InsertTransferDoc(in *TransferDoc, user_id string) error {
+	in.UserID = user_id
+	tx := dbmap.Begin()
+	var count int
+	err := tx.Model(&Warehouse{}).Where("id IN (?)", []uint{in.FromID, in.ToID}).
+		Count(&count).Error
+	if err == nil && count != 2 {
+		err = ErrMissing
+	}
+	products := in.productIDs()
+	if err == nil {
+		err = tx.Model(&Product{}).Where("id IN (?)", products).Count(&count).Error
+	}
+	if err == nil && count != len(products) {
+		err = ErrMissing
+	}
+	if err == nil {
+		err = tx.Create(&in.Transfer).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range in.Details {
+		in.Details[i].TransferID = in.ID
+		detail := in.Details[i].Detail()
+		onHand, err := lockStock(tx, detail.ProductID, in.FromID)
+		if err == nil && onHand < int(detail.Quantity) {
+			err = StockError{detail.ProductID, onHand, detail.Quantity}
+		}
+		if err == nil {
+			err = tx.Create(&detail).Error
+		}
+		if err == nil {
+			err = moveStock(tx, MoveTransfer, in.ID, detail.ProductID, in.FromID,
+				-int(detail.Quantity))
+		}
+		if err == nil {
+			err = moveStock(tx, MoveTransfer, in.ID, detail.ProductID, in.ToID,
+				int(detail.Quantity))
+		}
+		if err == nil {
+			err = fulfillBackOrders(tx, detail.ProductID, in.ToID)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	in.Sum()
+	err = tx.Commit().Error
+	if err == nil {
+		checkReorder(products...)
+	}
+	return err
+}
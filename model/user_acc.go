@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 var (
 	ADMIN   int8 = 2
@@ -11,15 +14,46 @@ var (
 //Represents, base of the admin, acquirement manager
 //and seller in the application
 type UserAcc struct {
-	Mail     string `json:"mail" binding:"required" gorm:"primary_key"`
+	Mail     string `json:"mail" binding:"required,email" gorm:"primary_key"`
 	Name     string `json:"name" binding:"required"`
 	Lastname string `json:"lastname" binding:"required"`
-	Rut      string `json:"rut" binding:"required"`
+	Rut      string `json:"rut" binding:"required,rut"`
 	Pass     string `json:"pass" binding:"required"`
-	Role     int8   `json:"role" binding:"required"`
+	Role     int8   `json:"role" binding:"min=0,max=2"`
 	Active   bool
 
+	//Second factor, the secret is pending until TOTPEnabled
+	TOTPSecret   string `json:"-"`
+	TOTPEnabled  bool   `json:"totp_enabled"`
+	TOTPLastStep int64  `json:"-"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
 }
+
+//MarshalJSON serializes an account without its password hash
+func (u UserAcc) MarshalJSON() ([]byte, error) {
+	type account UserAcc
+	return json.Marshal(struct {
+		account
+		Pass string `json:"pass,omitempty"`
+	}{account: account(u)})
+}
+
+//Represents the account fields that an admin can update
+type AccountUpdate struct {
+	Name     string `json:"name" binding:"required"`
+	Lastname string `json:"lastname" binding:"required"`
+	Rut      string `json:"rut" binding:"required,rut"`
+}
+
+//Represents a role change of an account
+type AccountRole struct {
+	Role *int8 `json:"role" binding:"exists,min=0,max=2"`
+}
+
+//Represents a new password of an account
+type AccountPass struct {
+	Pass string `json:"pass" binding:"required"`
+}
@@ -1,114 +1,27 @@
 package model
 
-import "strings"
-import "log"
+import (
+	"log"
+	"strings"
 
-//Return true in case of that all params are okay
-func CheckInCustomer(in Customer) bool {
-	if strings.Compare(in.Name, "") != 0 && strings.Compare(in.Rut, "") != 0 && strings.Compare(in.Mail, "") != 0 {
-		return true
-	} else {
-		return false
-	}
-}
-
-//Return true in case of that all params are okay
-func CheckInProvider(in Provider) bool {
-	if strings.Compare(in.Name, "") != 0 && strings.Compare(in.Rut, "") != 0 && strings.Compare(in.Mail, "") != 0 {
-		return true
-	} else {
-		return false
-	}
-}
-
-//Return true in case of that all params are okay
-func CheckInPurchaseDetail(in PurchaseDetail) bool {
-	if in.PurchaseID < 1 {
-		return false
-	} else if in.ProductID < 1 {
-		return false
-	} else if in.Price < 1 {
-		return false
-	} else if in.Quantity < 1 {
-		return false
-	}
-	return true
-}
-
-//Return true in case of that all params are okay
-func CheckInSaleDetail(in SaleDetail) bool {
-	if in.SaleID < 1 {
-		return false
-	} else if in.ProductID < 1 {
-		return false
-	} else if in.Price < 1 {
-		return false
-	} else if in.Quantity < 1 {
-		return false
-	}
-	return true
-}
-
-//Return false in case of that all params are okay
-func CheckInTagCustomer(in TagCustomer) bool {
-	if in.TagID < 0 || strings.Compare(in.CustomerID, "") == 0 {
-		return true
-	} else {
-		return false
-	}
-}
-
-//Return true in case of that all params are okay
-func CheckInTag(in Tag) bool {
-	if strings.Compare(in.Name, "") == 0 {
-		return true
-	} else {
-		return false
-	}
-}
+	"github.com/fabulias/coimco_backend/rut"
+)
 
-//Return true in case of that all params are okay
-func CheckInAccount(in UserAcc) bool {
-	var flag bool = false
-	if strings.Compare(in.Name, "") == 0 {
-		flag = true
-		return flag
-	} else if strings.Compare(in.Lastname, "") == 0 {
-		flag = true
-		return flag
-	} else if in.Role < 0 && in.Role > 2 {
-		flag = true
-		return flag
-	} else if strings.Compare(in.Mail, "") == 0 {
-		flag = true
-		return flag
-	} else if strings.Compare(in.Rut, "") == 0 {
-		flag = true
-		return flag
-	} else if strings.Compare(in.Pass, "") == 0 {
-		flag = true
-		return flag
+//normalRut return the RUT as it's stored,
+//RUTs that aren't valid are returned as they are
+func normalRut(in string) string {
+	if normal, err := rut.Normalize(in); err == nil {
+		return normal
 	}
-	return flag
+	return in
 }
 
-//Return true in case of that all params are okay
-func CheckInProduct(in Product) bool {
-	var flag bool = false
-	if strings.Compare(in.Name, "") != 0 {
-		flag = true
-		return flag
-	} else if strings.Compare(in.Details, "") != 0 {
-		flag = true
-		return flag
-	} else if strings.Compare(in.Brand, "") != 0 {
-		flag = true
-		return flag
-	} else if strings.Compare(in.Category, "") != 0 {
-		flag = true
-		return flag
+//normalRutFilter normalizes the RUT of a list filter,
+//prefix filters ending with '*' are kept as they are
+func normalRutFilter(in ListQuery, column string) {
+	if value, ok := in.Filters[column]; ok && !strings.HasSuffix(value, "*") {
+		in.Filters[column] = normalRut(value)
 	}
-	return flag
 }
 
 //Print error log
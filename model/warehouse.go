@@ -0,0 +1,54 @@
+package model
+
+import "github.com/jinzhu/gorm"
+
+//DefaultWarehouse is the warehouse of the documents and
+//movements that don't have one, it's created on start
+const DefaultWarehouse uint = 1
+
+//Warehouse represents a location where the stock is kept
+type Warehouse struct {
+	gorm.Model
+	Name    string `json:"name" binding:"required"`
+	Address string `json:"address"`
+}
+
+//WarehouseFields are the columns of warehouses lists
+var WarehouseFields = ListFields{
+	Key:     "id",
+	Columns: []string{"id", "name", "address", "created_at", "updated_at"},
+}
+
+//WarehouseStock represents the quantity on hand of a product in a
+//warehouse, it's read from the warehouse_stock view
+type WarehouseStock struct {
+	WarehouseID   uint   `json:"warehouse_id"`
+	WarehouseName string `json:"warehouse_name"`
+	Stock
+}
+
+//TableName return the view of the stock of products by warehouse
+func (WarehouseStock) TableName() string {
+	return "warehouse_stock"
+}
+
+//WarehouseStockFields are the columns of warehouse stock lists
+var WarehouseStockFields = ListFields{
+	Key:     "product_id",
+	Amount:  "on_hand",
+	Columns: []string{"warehouse_id", "product_id", "name", "brand", "category", "on_hand"},
+}
+
+//Tables with a warehouse, their rows saved before
+//warehouses existed are moved to the default one
+var warehouseTables = []string{"purchase", "sale", "stock_movement", "back_order", "stock_count"}
+
+//warehouseStockView is the SQL of the warehouse_stock view,
+//products appear in the warehouses where they were moved
+const warehouseStockView = "CREATE VIEW warehouse_stock AS " +
+	"SELECT warehouse.id AS warehouse_id, warehouse.name AS warehouse_name, " +
+	"product.id AS product_id, product.name, product.brand, product.category, " +
+	"SUM(stock_movement.quantity)::bigint AS on_hand " +
+	"FROM stock_movement JOIN product ON product.id = stock_movement.product_id " +
+	"JOIN warehouse ON warehouse.id = stock_movement.warehouse_id " +
+	"WHERE product.deleted_at IS NULL GROUP BY warehouse.id, product.id"
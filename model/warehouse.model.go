@@ -0,0 +1,129 @@
+package model
+
+import (
+	"strconv"
+
+	"github.com/jinzhu/gorm"
+)
+
+//This function allow obtain a page of warehouses' resource.
+func GetWarehouses(in ListQuery) ([]Warehouse, Page, error) {
+	var warehouses []Warehouse
+	page, err := list(&warehouses, in, WarehouseFields)
+	return warehouses, page, err
+}
+
+//GetWarehouse return a warehouse with an ID
+func GetWarehouse(id uint) (Warehouse, error) {
+	var warehouse Warehouse
+	err := dbmap.First(&warehouse, id).Error
+	checkErr(err, selectOneFailed)
+	return warehouse, err
+}
+
+//This function allow insert warehouse' resource
+func InsertWarehouse(in *Warehouse) (*Warehouse, bool) {
+	err := dbmap.Create(in).Error
+	return in, err == nil
+}
+
+//This function allow update warehouse' resource for his id.
+func UpdateWarehouse(id uint, in Warehouse) (Warehouse, error) {
+	warehouse, err := GetWarehouse(id)
+	if err != nil {
+		return warehouse, err
+	}
+	err = dbmap.Model(&warehouse).Updates(map[string]interface{}{
+		"name":    in.Name,
+		"address": in.Address,
+	}).Error
+	return warehouse, err
+}
+
+//This function allow soft delete warehouse' resource for his id,
+//it fails if it's the default warehouse or it has stock on hand.
+func DeleteWarehouse(id uint) (Warehouse, error) {
+	warehouse, err := GetWarehouse(id)
+	if err != nil {
+		return warehouse, err
+	}
+	var count int
+	err = dbmap.Model(&WarehouseStock{}).
+		Where("warehouse_id = ? AND on_hand <> 0", id).Count(&count).Error
+	if err != nil {
+		return warehouse, err
+	}
+	if id == DefaultWarehouse || count > 0 {
+		return warehouse, ErrReferenced
+	}
+	//Pending back-orders and open counts still need the warehouse
+	err = dbmap.Model(&BackOrder{}).
+		Where("warehouse_id = ? AND pending > 0", id).Count(&count).Error
+	if err != nil {
+		return warehouse, err
+	}
+	if count > 0 {
+		return warehouse, ErrReferenced
+	}
+	err = dbmap.Model(&StockCount{}).
+		Where("warehouse_id = ? AND status = ?", id, CountOpen).Count(&count).Error
+	if err != nil {
+		return warehouse, err
+	}
+	if count > 0 {
+		return warehouse, ErrReferenced
+	}
+	err = dbmap.Delete(&warehouse).Error
+	return warehouse, err
+}
+
+//This function allow restore a soft deleted warehouse' resource.
+func RestoreWarehouse(id uint) (Warehouse, error) {
+	res := dbmap.Unscoped().Model(&Warehouse{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		UpdateColumn("deleted_at", gorm.Expr("NULL"))
+	if res.Error != nil {
+		return Warehouse{}, res.Error
+	}
+	if res.RowsAffected != 1 {
+		return Warehouse{}, ErrNotDeleted
+	}
+	return GetWarehouse(id)
+}
+
+//GetWarehouseStocks return a page of the stock of products in a warehouse
+func GetWarehouseStocks(id uint, in ListQuery) ([]WarehouseStock, Page, error) {
+	var stocks []WarehouseStock
+	in.Filters["warehouse_id"] = strconv.FormatUint(uint64(id), 10)
+	page, err := list(&stocks, in, WarehouseStockFields)
+	return stocks, page, err
+}
+
+//checkWarehouse return the warehouse of a document, the default one if
+//it's zero, it fails with ErrMissing if the warehouse doesn't exist
+func checkWarehouse(tx *gorm.DB, id uint) (uint, error) {
+	if id == 0 {
+		id = DefaultWarehouse
+	}
+	var count int
+	err := tx.Model(&Warehouse{}).Where("id = ?", id).Count(&count).Error
+	if err == nil && count == 0 {
+		err = ErrMissing
+	}
+	return id, err
+}
+
+//initWarehouses creates the default warehouse and moves
+//to it the rows saved before warehouses existed
+func initWarehouses(db *gorm.DB) {
+	var count int
+	db.Unscoped().Model(&Warehouse{}).Count(&count)
+	if count == 0 {
+		db.Create(&Warehouse{Name: "Principal"})
+	}
+	for _, table := range warehouseTables {
+		db.Exec("UPDATE "+table+" SET warehouse_id = ? WHERE warehouse_id IS NULL OR warehouse_id = 0",
+			DefaultWarehouse)
+		db.Table(table).AddForeignKey("warehouse_id", "warehouse(id)", "RESTRICT", "RESTRICT")
+	}
+}
@@ -1,9 +1,10 @@
 package routes
 
 import (
-	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/fabulias/coimco_backend/auth"
 	"github.com/fabulias/coimco_backend/hash"
 	"github.com/fabulias/coimco_backend/model"
 	"github.com/gin-gonic/gin"
@@ -12,18 +13,7 @@ import (
 //This route insert an account in user_acc table
 func PostAccount(c *gin.Context) {
 	var in model.UserAcc
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
-	if err != nil {
-		log.Println(err.Error())
-	}
-	if model.CheckInAccount(in) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": ErrorParams,
-		}
-		c.JSON(http.StatusBadRequest, response)
+	if !bindJSON(c, &in) {
 		return
 	}
 	//As the params are correct, we proceeded
@@ -79,3 +69,180 @@ func GetAccount(c *gin.Context) {
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+//This route deactivates an account with a 'mail'
+func DeactivateAccount(c *gin.Context) {
+	mail := c.Param("mail")
+	//Admins can't lock themselves out
+	if mail == auth.Mail(c) {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorDeactivateSelf,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	setActiveAccount(c, mail, false)
+}
+
+//This route reactivates an account with a 'mail'
+func ReactivateAccount(c *gin.Context) {
+	setActiveAccount(c, c.Param("mail"), true)
+}
+
+//setActiveAccount changes the state of an account and writes the response
+func setActiveAccount(c *gin.Context, mail string, active bool) {
+	auditAccount(c, mail)
+	account, err := model.SetActiveAccount(mail, active)
+	accountResponse(c, account, err)
+}
+
+//This route return accounts, they can be filtered
+//by 'role' and 'active' query params
+func GetAccounts(c *gin.Context) {
+	var role *int8
+	var active *bool
+	if query := c.Query("role"); query != "" {
+		value, err := strconv.ParseInt(query, 10, 8)
+		if err != nil {
+			response := gin.H{
+				"status":  "error",
+				"data":    nil,
+				"message": ErrorParams,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+		r := int8(value)
+		role = &r
+	}
+	if query := c.Query("active"); query != "" {
+		value, err := strconv.ParseBool(query)
+		if err != nil {
+			response := gin.H{
+				"status":  "error",
+				"data":    nil,
+				"message": ErrorParams,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+		active = &value
+	}
+	accounts, err := model.GetAccounts(role, active)
+	if err != nil || len(accounts) == 0 {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorPlural + " accounts",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    accounts,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route updates name, lastname and rut of an account
+func PutAccount(c *gin.Context) {
+	var in model.AccountUpdate
+	if !bindJSON(c, &in) {
+		return
+	}
+	auditAccount(c, c.Param("mail"))
+	account, err := model.UpdateAccount(c.Param("mail"), in)
+	accountResponse(c, account, err)
+}
+
+//This route changes the role of an account
+func PutAccountRole(c *gin.Context) {
+	var in model.AccountRole
+	if !bindJSON(c, &in) {
+		return
+	}
+	mail := c.Param("mail")
+	//Admins can't lose their own role
+	if mail == auth.Mail(c) {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorModifySelf,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	auditAccount(c, mail)
+	account, err := model.SetRoleAccount(mail, *in.Role)
+	accountResponse(c, account, err)
+}
+
+//This route resets the password of an account
+func PutAccountPassword(c *gin.Context) {
+	var in model.AccountPass
+	if !bindJSON(c, &in) {
+		return
+	}
+	hash_pass, err := hash.HashPassword(in.Pass)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorHashPassword,
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	auditAccount(c, c.Param("mail"))
+	account, err := model.SetPassAccount(c.Param("mail"), hash_pass)
+	accountResponse(c, account, err)
+}
+
+//This route soft deletes an account
+func DeleteAccount(c *gin.Context) {
+	mail := c.Param("mail")
+	//Admins can't delete themselves
+	if mail == auth.Mail(c) {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorModifySelf,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	auditAccount(c, mail)
+	account, err := model.DeleteAccount(mail)
+	accountResponse(c, account, err)
+}
+
+//auditAccount sets the account before its modification in the audit log
+func auditAccount(c *gin.Context, mail string) {
+	account, err := model.GetAccount(mail)
+	if err == nil {
+		auditBefore(c, account)
+	}
+}
+
+//accountResponse writes the response of an account modification
+func accountResponse(c *gin.Context, account model.UserAcc, err error) {
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " account with that mail",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    account,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
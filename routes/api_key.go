@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/fabulias/coimco_backend/auth"
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//GetAPIKeys return all API keys without their secrets
+func GetAPIKeys(c *gin.Context) {
+	keys, err := model.GetAPIKeys()
+	if err != nil || len(keys) == 0 {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorPlural + " API keys",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    keys,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//PostAPIKey creates an API key, the key is only shown in this response
+func PostAPIKey(c *gin.Context) {
+	var in model.APIKeyInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	key, token, err := auth.CreateAPIKey(in, auth.Mail(c))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": PostMessageError + " an API key",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	response := gin.H{
+		"status":  "success",
+		"data":    key,
+		"key":     token,
+		"message": nil,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+//DeleteAPIKey revokes an API key
+func DeleteAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	key, err := model.GetAPIKey(uint(id))
+	if err == nil {
+		auditBefore(c, key)
+		key, err = model.RevokeAPIKey(uint(id))
+	}
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " API key with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    key,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,188 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabulias/coimco_backend/auth"
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//Keys used by handlers to complete the audit log entry
+const (
+	auditBeforeKey = "audit_before"
+	auditHideKey   = "audit_hide"
+	auditActorKey  = "audit_actor"
+)
+
+//Default number of entries returned by the audit log
+var auditLimit = 100
+
+//Actions of the audit log by HTTP method
+var auditActions = map[string]string{
+	"POST":   "create",
+	"PUT":    "update",
+	"PATCH":  "update",
+	"DELETE": "delete",
+}
+
+//Fields of the response data used as entity key
+//when the route doesn't have params
+var auditKeyFields = []string{"ID", "id", "rut", "mail"}
+
+//auditWriter keeps a copy of the response body
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+//This Middleware function,
+//it saves an audit log entry of the entity when the write succeeds.
+//The entry has the data of the response as After
+func Audit(entity string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &auditWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+		if c.Writer.Status() < 200 || c.Writer.Status() >= 300 {
+			return
+		}
+		var response struct {
+			Data json.RawMessage `json:"data"`
+		}
+		json.Unmarshal(writer.body.Bytes(), &response)
+		entry := model.AuditLog{
+			Actor:     auditActorOf(c),
+			Action:    auditActions[c.Request.Method],
+			Entity:    entity,
+			EntityKey: auditKey(c, response.Data),
+			Path:      c.Request.URL.Path,
+		}
+		if _, hide := c.Get(auditHideKey); !hide {
+			entry.After = string(response.Data)
+		}
+		if before, ok := c.Get(auditBeforeKey); ok {
+			doc, err := json.Marshal(before)
+			checkErr(err, AuditError)
+			entry.Before = string(doc)
+		}
+		_, err := model.InsertAuditLog(&entry)
+		checkErr(err, AuditError)
+	}
+}
+
+//auditBefore sets the state of the entity before the write
+func auditBefore(c *gin.Context, before interface{}) {
+	c.Set(auditBeforeKey, before)
+}
+
+//auditActor sets the actor of routes without token,
+//e.g. the account whose password is reset
+func auditActor(c *gin.Context, mail string) {
+	c.Set(auditActorKey, mail)
+}
+
+//auditActorOf returns the account that made the request
+func auditActorOf(c *gin.Context) string {
+	if mail, ok := c.Get(auditActorKey); ok {
+		return mail.(string)
+	}
+	return auth.Mail(c)
+}
+
+//auditHide avoids saving the response data, it's used when
+//the data contains secrets
+func auditHide(c *gin.Context) {
+	c.Set(auditHideKey, true)
+}
+
+//auditKey returns the params of the route, or the key
+//fields of the response data if the route doesn't have params
+func auditKey(c *gin.Context, data json.RawMessage) string {
+	var keys []string
+	for _, param := range c.Params {
+		keys = append(keys, param.Key+"="+param.Value)
+	}
+	if len(keys) > 0 {
+		return strings.Join(keys, ",")
+	}
+	var fields map[string]interface{}
+	if json.Unmarshal(data, &fields) != nil {
+		return ""
+	}
+	for _, field := range auditKeyFields {
+		if value, ok := fields[field]; ok {
+			keys = append(keys, fmt.Sprintf("%s=%v", field, value))
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+//GetAuditLogs return the audit log, it can be filtered by 'user',
+//'entity', 'start' and 'end' (RFC 3339) and limited by 'limit' query params
+func GetAuditLogs(c *gin.Context) {
+	filter := model.AuditFilter{
+		Actor:  c.Query("user"),
+		Entity: c.Query("entity"),
+		Limit:  auditLimit,
+	}
+	var err error
+	if query := c.Query("limit"); query != "" {
+		filter.Limit, err = strconv.Atoi(query)
+		if err == nil && filter.Limit < 1 {
+			err = fmt.Errorf("limit must be positive")
+		}
+	}
+	if query := c.Query("start"); query != "" && err == nil {
+		var start time.Time
+		start, err = time.Parse(time.RFC3339, query)
+		filter.Start = &start
+	}
+	if query := c.Query("end"); query != "" && err == nil {
+		var end time.Time
+		end, err = time.Parse(time.RFC3339, query)
+		filter.End = &end
+	}
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	logs, err := model.GetAuditLogs(filter)
+	if err != nil || len(logs) == 0 {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorPlural + " audit logs",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    logs,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/fabulias/coimco_backend/auth"
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//This route asking for a page of back-orders, 'min=1' lists the pending
+//ones, see listQuery for the query params. Sellers only see their own
+func GetBackOrders(c *gin.Context) {
+	in, ok := listQuery(c, model.BackOrderFields)
+	if !ok {
+		return
+	}
+	backOrdersResponse(c, in)
+}
+
+//This route asking for a page of back-orders of a customer with a 'rut'
+func GetCustomerBackOrders(c *gin.Context) {
+	in, ok := listQuery(c, model.BackOrderFields)
+	if !ok {
+		return
+	}
+	in.Filters["customer_id"] = c.Param("rut")
+	backOrdersResponse(c, in)
+}
+
+//backOrdersResponse responds the page of back-orders, scoped to the seller
+func backOrdersResponse(c *gin.Context, in model.ListQuery) {
+	if auth.Role(c) == model.SELLER {
+		in.Filters["user_id"] = auth.Mail(c)
+	}
+	backOrders, page, err := model.GetBackOrders(in)
+	listResponse(c, "back-orders", backOrders, len(backOrders), page, err)
+}
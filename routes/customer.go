@@ -7,27 +7,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-//This route asking for all customers
+//This route asking for a page of customers, see listQuery for
+//the pagination, sorting and filtering query params
 func GetCustomers(c *gin.Context) {
-	//Asking to model
-	customers := model.GetCustomers()
-	//If length of customers is zero,
-	//is because no exist customers
-	if checkSize(customers) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": GetMessageErrorPlural + " clients",
-		}
-		c.JSON(http.StatusNotFound, response)
-	} else {
-		response := gin.H{
-			"status":  "success",
-			"data":    customers,
-			"message": nil,
-		}
-		c.JSON(http.StatusOK, response)
+	in, ok := listQuery(c, model.AgentFields)
+	if !ok {
+		return
 	}
+	customers, page, err := model.GetCustomers(in)
+	listResponse(c, "clients", customers, len(customers), page, err)
 }
 
 //This route return a client with a 'rut'
@@ -54,16 +42,8 @@ func GetCustomer(c *gin.Context) {
 //This route insert a customer in his table
 func PostCustomer(c *gin.Context) {
 	var in model.Customer
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
 	//Check if client parameters are valid
-	if !model.CheckInCustomer(in) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": ErrorParams,
-		}
-		c.JSON(http.StatusBadRequest, response)
+	if !bindJSON(c, &in) {
 		return
 	}
 	//As the params are correct, we proceeded
@@ -87,11 +67,144 @@ func PostCustomer(c *gin.Context) {
 	}
 }
 
+//This route updates a customer with a 'rut', every field is replaced
+func PutCustomer(c *gin.Context) {
+	var in model.Customer
+	updateCustomer(c, in)
+}
+
+//This route updates a customer with a 'rut', only fields in body are replaced
+func PatchCustomer(c *gin.Context) {
+	in, err := model.GetCustomer(c.Param("rut"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " client with that rut",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	updateCustomer(c, in)
+}
+
+//updateCustomer decodes the body over 'in' and updates the customer
+func updateCustomer(c *gin.Context, in model.Customer) {
+	rut := c.Param("rut")
+	err := decodeJSON(c, &in)
+	checkErr(err, BindJson)
+	//Rut is the key, it can't be changed
+	in.Rut = rut
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if !validRequest(c, &in) {
+		return
+	}
+	if before, err := model.GetCustomer(rut); err == nil {
+		auditBefore(c, before)
+	}
+	customer, err := model.UpdateCustomer(rut, in)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " client with that rut",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    customer,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route deletes a customer with a 'rut', it's a soft delete unless
+//'hard' query param is true, hard deletes fail if sales reference it
+func DeleteCustomer(c *gin.Context) {
+	rut := c.Param("rut")
+	hard := c.Query("hard") == "true"
+	customer, err := model.GetCustomer(rut)
+	//Soft deleted ones can be deleted permanently
+	if hard {
+		customer, err = model.GetAnyCustomer(rut)
+	}
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " client with that rut",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	auditBefore(c, customer)
+	if hard {
+		err = model.HardDeleteCustomer(rut)
+	} else {
+		customer, err = model.DeleteCustomer(rut)
+	}
+	if err == model.ErrReferenced {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+	} else if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": DeleteMessageError + " a client",
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    customer,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route restores a soft deleted customer with a 'rut'
+func RestoreCustomer(c *gin.Context) {
+	customer, err := model.RestoreCustomer(c.Param("rut"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " deleted client with that rut",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    customer,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 //GetRankCustomerK make route to stats model
 func GetRankCustomerK(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	customers, err := model.GetRankCustomerK(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -100,22 +213,12 @@ func GetRankCustomerK(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		customers, err := model.GetRankCustomerK(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    customers,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    customers,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -123,7 +226,10 @@ func GetRankCustomerK(c *gin.Context) {
 func GetProductTotal(c *gin.Context) {
 	id := c.Param("id_customer")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetProductTotal(id, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -132,22 +238,12 @@ func GetProductTotal(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetProductTotal(id, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -155,7 +251,10 @@ func GetProductTotal(c *gin.Context) {
 func GetTotalCash(c *gin.Context) {
 	id := c.Param("id_customer")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	total_cash, err := model.GetTotalCash(id, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -164,22 +263,12 @@ func GetTotalCash(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		total_cash, err := model.GetTotalCash(id, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    total_cash,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    total_cash,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -187,7 +276,10 @@ func GetTotalCash(c *gin.Context) {
 func GetRankFrequency(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	total_cash, err := model.GetRankFrequency(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -196,22 +288,12 @@ func GetRankFrequency(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		total_cash, err := model.GetRankFrequency(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    total_cash,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    total_cash,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -220,7 +302,10 @@ func GetRankCustomerKL(c *gin.Context) {
 	k := c.Param("k")
 	l := c.Param("l")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	customers, err := model.GetRankCustomerKL(k, l, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -229,22 +314,12 @@ func GetRankCustomerKL(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		customers, err := model.GetRankCustomerKL(k, l, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    customers,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    customers,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -252,7 +327,10 @@ func GetRankCustomerKL(c *gin.Context) {
 func GetRankCustomerVariety(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	customers, err := model.GetRankCustomerVariety(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -261,21 +339,11 @@ func GetRankCustomerVariety(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		customers, err := model.GetRankCustomerVariety(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    customers,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    customers,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
@@ -3,14 +3,16 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
 
+	"github.com/fabulias/coimco_backend/auth"
 	"github.com/fabulias/coimco_backend/model"
 )
 
 //GetInformationDashboard make route to dashboard model
 func GetInformationDashboard(c *gin.Context) {
 	role := c.Param("role")
-	id := c.Param("id_seller")
+	id := sellerScope(c, "id_seller")
 	if role == "" {
 		response := gin.H{
 			"status":  "error",
@@ -18,6 +20,14 @@ func GetInformationDashboard(c *gin.Context) {
 			"message": ErrorParams,
 		}
 		c.JSON(http.StatusBadRequest, response)
+	} else if role != strconv.Itoa(int(model.SELLER)) && auth.Role(c) == model.SELLER {
+		//Sellers only can see their own dashboard
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ForbiddenDashboard,
+		}
+		c.JSON(http.StatusForbidden, response)
 	} else {
 		products, err1, err2 := model.GetInformationDashboard(role, id)
 		if err1 != nil || err2 != nil {
@@ -0,0 +1,118 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//Default and maximum number of rows returned by lists
+var (
+	listLimit    = 50
+	listMaxLimit = 500
+)
+
+//Header with the number of rows that match the list filters
+const totalCountHeader = "X-Total-Count"
+
+//listQuery return the pagination, sorting and filtering of a list.
+//It reads 'limit', 'offset', 'cursor', 'sort' ('-' prefix sorts descending),
+//'deleted', for lists with dates 'start' and 'end' (RFC 3339) and for lists
+//with amounts 'min' and 'max' query params, other params named as a column
+//are filters.
+//It responds 400 and return false if the params aren't valid
+func listQuery(c *gin.Context, fields model.ListFields) (model.ListQuery, bool) {
+	in := model.ListQuery{
+		Limit:   listLimit,
+		Cursor:  c.Query("cursor"),
+		Deleted: c.Query("deleted") == "true",
+		Filters: map[string]string{},
+	}
+	var err error
+	if query := c.Query("limit"); query != "" {
+		in.Limit, err = strconv.Atoi(query)
+		if err == nil && (in.Limit < 1 || in.Limit > listMaxLimit) {
+			err = strconv.ErrRange
+		}
+	}
+	if query := c.Query("offset"); query != "" && err == nil {
+		in.Offset, err = strconv.Atoi(query)
+		if err == nil && in.Offset < 0 {
+			err = strconv.ErrRange
+		}
+	}
+	if query := c.Query("start"); query != "" && fields.Date != "" && err == nil {
+		var start time.Time
+		start, err = time.Parse(time.RFC3339, query)
+		in.Start = &start
+	}
+	if query := c.Query("end"); query != "" && fields.Date != "" && err == nil {
+		var end time.Time
+		end, err = time.Parse(time.RFC3339, query)
+		in.End = &end
+	}
+	if query := c.Query("min"); query != "" && fields.Amount != "" && err == nil {
+		var min int
+		min, err = strconv.Atoi(query)
+		in.Min = &min
+	}
+	if query := c.Query("max"); query != "" && fields.Amount != "" && err == nil {
+		var max int
+		max, err = strconv.Atoi(query)
+		in.Max = &max
+	}
+	in.Sort = c.Query("sort")
+	if strings.HasPrefix(in.Sort, "-") {
+		in.Sort, in.Desc = in.Sort[1:], true
+	}
+	for param, values := range c.Request.URL.Query() {
+		if fields.Has(param) && values[0] != "" {
+			in.Filters[param] = values[0]
+		}
+	}
+	if err != nil || (in.Sort != "" && !fields.Has(in.Sort)) {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return in, false
+	}
+	return in, true
+}
+
+//listResponse responds a page of a list, 'size' is the number
+//of rows in 'data' and 'name' is used when there are no rows
+func listResponse(c *gin.Context, name string, data interface{}, size int, page model.Page, err error) {
+	if err == model.ErrCursor {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	c.Header(totalCountHeader, strconv.Itoa(page.Total))
+	if err != nil || size == 0 {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorPlural + " " + name,
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    data,
+			"page":    page,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//Default number of entries returned by the login history
+var loginHistoryLimit = 100
+
+//GetLockouts return accounts and IPs with failed sign in attempts
+func GetLockouts(c *gin.Context) {
+	lockouts, err := model.GetLockouts()
+	if err != nil || len(lockouts) == 0 {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorPlural + " lockouts",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    lockouts,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//DeleteLockout clears the failed attempts of a key,
+//the key is "mail:<mail>" or "ip:<ip>"
+func DeleteLockout(c *gin.Context) {
+	key := c.Param("key")
+	if lockout, err := model.GetLockout(key); err == nil {
+		auditBefore(c, lockout)
+	}
+	err := model.ClearLockout(key)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    key,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//GetLoginHistory return the last sign in attempts,
+//they can be filtered by 'mail' and limited with 'limit' query params
+func GetLoginHistory(c *gin.Context) {
+	limit := loginHistoryLimit
+	if query := c.Query("limit"); query != "" {
+		value, err := strconv.Atoi(query)
+		if err != nil || value < 1 {
+			response := gin.H{
+				"status":  "error",
+				"data":    nil,
+				"message": ErrorParams,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+		limit = value
+	}
+	attempts, err := model.GetLoginAttempts(c.Query("mail"), limit)
+	if err != nil || len(attempts) == 0 {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorPlural + " login attempts",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    attempts,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
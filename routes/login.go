@@ -1,36 +1,33 @@
 package routes
 
 import (
-	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/fabulias/coimco_backend/auth"
 	"github.com/fabulias/coimco_backend/model"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 //This route generates the logic to enter in the application
 func Login(c *gin.Context) {
 	var in model.Login
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
-	if err != nil {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": BindJson,
-		}
-		c.JSON(http.StatusBadRequest, response)
+	if !bindJSON(c, &in) {
+		return
+	}
+	//Too many failed attempts delay or lock sign in
+	if loginLocked(c, in.Mail) {
 		return
 	}
 	//Check if 'in' exist in accounts with that
 	//mail and pass
-	log.Println("in -> ", in)
 	acc, ret := model.LoginP(in)
-	if ret {
-		//Generate the token for this account
-		token, errT := auth.CreateToken(in.Mail)
-		if errT != nil {
+	if ret && auth.SecondFactorPending(acc) {
+		//The token is issued after the second factor
+		challenge, err := auth.CreateChallenge(acc.Mail)
+		if err != nil {
 			response := gin.H{
 				"status":  "error",
 				"data":    nil,
@@ -39,19 +36,22 @@ func Login(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, response)
 			return
 		}
-		//Account information
 		data := gin.H{
-			"name":     acc.Name,
-			"lastname": acc.Lastname,
-			"role":     acc.Role,
+			"totp_enabled":  acc.TOTPEnabled,
+			"totp_required": auth.TOTPRequired(acc.Role),
 		}
 		response := gin.H{
-			"status":  "success",
-			"data":    data,
-			"token":   token,
-			"message": LoginOK,
+			"status":    "success",
+			"data":      data,
+			"challenge": challenge,
+			"message":   LoginSecondFactor,
 		}
 		c.JSON(http.StatusOK, response)
+		return
+	}
+	loginAttempt(c, in.Mail, ret)
+	if ret {
+		loginSuccess(c, acc, nil)
 	} else {
 		response := gin.H{
 			"status":  "error",
@@ -61,3 +61,158 @@ func Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, response)
 	}
 }
+
+//loginLocked writes the response and returns true
+//if sign in is locked for that mail or the client IP
+func loginLocked(c *gin.Context, mail string) bool {
+	until, locked := model.LoginLocked(model.MailKey(mail),
+		model.IPKey(c.ClientIP()))
+	if !locked {
+		return false
+	}
+	retry := int(time.Until(until).Seconds()) + 1
+	c.Header("Retry-After", strconv.Itoa(retry))
+	response := gin.H{
+		"status":  "error",
+		"data":    nil,
+		"message": LoginLocked,
+	}
+	c.JSON(http.StatusTooManyRequests, response)
+	return true
+}
+
+//loginAttempt saves a sign in attempt and updates the lockouts
+func loginAttempt(c *gin.Context, mail string, success bool) {
+	ip := c.ClientIP()
+	attempt := model.LoginAttempt{
+		Mail:      mail,
+		IP:        ip,
+		UserAgent: c.Request.UserAgent(),
+		Success:   success,
+	}
+	_, err := model.InsertLoginAttempt(&attempt)
+	checkErr(err, LoginHistoryError)
+	if success {
+		err = model.ClearLockout(model.MailKey(mail))
+		checkErr(err, LoginHistoryError)
+	} else {
+		_, err = model.LoginFailed(model.MailKey(mail), model.AccountPolicy)
+		checkErr(err, LoginHistoryError)
+		_, err = model.LoginFailed(model.IPKey(ip), model.IPPolicy)
+		checkErr(err, LoginHistoryError)
+	}
+}
+
+//loginSuccess generates the tokens of an account and writes the response,
+//extra data is added to the account information
+func loginSuccess(c *gin.Context, acc model.UserAcc, extra gin.H) {
+	var refresh string
+	//Generate the token for this account
+	token, errT := auth.CreateToken(acc.Mail, acc.Role)
+	if errT == nil {
+		refresh, errT = auth.CreateRefreshToken(acc.Mail)
+	}
+	if errT != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": TokenError,
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	//Account information
+	data := gin.H{
+		"name":     acc.Name,
+		"lastname": acc.Lastname,
+		"role":     acc.Role,
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+	response := gin.H{
+		"status":        "success",
+		"data":          data,
+		"token":         token,
+		"refresh_token": refresh,
+		"message":       LoginOK,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+//This route rotates a refresh token and generates a new access token
+func RefreshToken(c *gin.Context) {
+	var in model.Refresh
+	if !bindJSON(c, &in) {
+		return
+	}
+	if checkSize(in.Token) {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	acc, token, refresh, err := auth.RotateRefreshToken(in.Token)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	//Account information
+	data := gin.H{
+		"name":     acc.Name,
+		"lastname": acc.Lastname,
+		"role":     acc.Role,
+	}
+	response := gin.H{
+		"status":        "success",
+		"data":          data,
+		"token":         token,
+		"refresh_token": refresh,
+		"message":       RefreshOK,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+//This route revokes the access token of the request
+//and the refresh token received
+func Logout(c *gin.Context) {
+	var in model.Refresh
+	//The refresh token is optional, so the body isn't required
+	binding.JSON.Bind(c.Request, &in)
+	if !checkSize(in.Token) {
+		err := auth.RevokeRefreshToken(auth.Mail(c), in.Token)
+		if err != nil {
+			response := gin.H{
+				"status":  "error",
+				"data":    nil,
+				"message": err.Error(),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+	err := auth.RevokeToken(c)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": LogoutError,
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	response := gin.H{
+		"status":  "success",
+		"data":    nil,
+		"message": LogoutOK,
+	}
+	c.JSON(http.StatusOK, response)
+}
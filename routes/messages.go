@@ -5,12 +5,35 @@ var (
 	GetMessageErrorPlural   = "There are no"
 	GetMessageErrorSingular = "There is not"
 	PostMessageError        = "Error inserting"
+	DeleteMessageError      = "Error deleting"
 	ErrorParams             = "Error in query params"
+	ValidationError         = "The request has fields that aren't valid"
 	BindJson                = "Error binding json"
 	LoginOK                 = "Mail and pass are correct, token it's OK"
 	LoginError              = "Mail or pass aren't correct"
+	LoginLocked             = "Too many failed attempts, try again later"
+	LoginSecondFactor       = "Mail and pass are correct, second factor is required"
+	TOTPEnrollOK            = "Scan the URI with an authenticator app and confirm a code"
+	TOTPConfirmOK           = "Second factor enabled, keep the recovery codes safe"
+	TOTPDisableOK           = "Second factor disabled"
+	LoginHistoryError       = "Error saving login attempt"
 	TokenError              = "Error creating token"
+	RefreshOK               = "Refresh token is correct, token it's OK"
+	LogoutOK                = "Tokens were revoked"
+	LogoutError             = "Error revoking token"
 	ErrorHashPassword       = "Error hashing password"
+	PasswordError           = "The password isn't correct"
+	PasswordChangeOK        = "Password was changed"
+	PasswordChangeError     = "Error changing password"
+	PasswordForgotOK        = "If the account exists, a reset token was sent to its mail"
+	PasswordResetSubject    = "Coimco password reset"
+	PasswordResetMailError  = "Error sending password reset mail"
+	ErrorDeactivateSelf     = "You can't deactivate your own account"
+	ErrorModifySelf         = "You can't change the role or delete your own account"
+	AuditError              = "Error saving audit log"
+	ReorderSubject          = "Coimco product to reorder"
+	ReorderMailError        = "Error sending reorder mail"
 	DashBoardErrFirst       = "Error in first query"
 	DashBoardErrSecond      = "Error in second query"
+	ForbiddenDashboard      = "Your role doesn't have access to this dashboard"
 )
@@ -0,0 +1,117 @@
+package routes
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/fabulias/coimco_backend/auth"
+	"github.com/fabulias/coimco_backend/hash"
+	"github.com/fabulias/coimco_backend/mailer"
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//Mailer used to deliver password reset tokens
+var sender = mailer.New()
+
+//This route changes the password of the account that made the request
+func PutMyPassword(c *gin.Context) {
+	var in model.PasswordChange
+	if !bindJSON(c, &in) {
+		return
+	}
+	mail := auth.Mail(c)
+	account, err := model.GetAccount(mail)
+	//Old password must be correct
+	if err != nil || !hash.CheckPasswordHash(in.OldPass, account.Pass) {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": PasswordError,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	hash_pass, err := hash.HashPassword(in.Pass)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorHashPassword,
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	//Other sessions are closed, this one gets a new refresh token
+	_, err = model.SetPassAccount(mail, hash_pass)
+	var refresh string
+	if err == nil {
+		refresh, err = auth.CreateRefreshToken(mail)
+	}
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": PasswordChangeError,
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	response := gin.H{
+		"status":        "success",
+		"data":          nil,
+		"refresh_token": refresh,
+		"message":       PasswordChangeOK,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+//This route sends a password reset token to the mail of an account.
+//The response is the same if the account doesn't exist
+func ForgotPassword(c *gin.Context) {
+	var in model.PasswordForgot
+	if !bindJSON(c, &in) {
+		return
+	}
+	token, err := auth.CreateResetToken(in.Mail)
+	if err == nil {
+		body := "Use this token to reset your password: " + token
+		if url := os.Getenv("RESET_URL"); url != "" {
+			body = "Open this link to reset your password: " +
+				url + "?token=" + token
+		}
+		err = sender.Send(in.Mail, PasswordResetSubject, body)
+		checkErr(err, PasswordResetMailError)
+	}
+	response := gin.H{
+		"status":  "success",
+		"data":    nil,
+		"message": PasswordForgotOK,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+//This route changes a password using a reset token
+func ResetPassword(c *gin.Context) {
+	var in model.PasswordReset
+	if !bindJSON(c, &in) {
+		return
+	}
+	account, err := auth.ResetPassword(in.Token, in.Pass)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	auditActor(c, account.Mail)
+	response := gin.H{
+		"status":  "success",
+		"data":    nil,
+		"message": PasswordChangeOK,
+	}
+	c.JSON(http.StatusOK, response)
+}
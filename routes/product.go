@@ -8,27 +8,15 @@ import (
 	"github.com/fabulias/coimco_backend/model"
 )
 
-//This route asking for all products
+//This route asking for a page of products, see listQuery for
+//the pagination, sorting and filtering query params
 func GetProducts(c *gin.Context) {
-	//Asking to model
-	products := model.GetProducts()
-	//If length of products is zero,
-	//is because no exist products
-	if checkSize(products) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": GetMessageErrorPlural + " products",
-		}
-		c.JSON(http.StatusNotFound, response)
-	} else {
-		response := gin.H{
-			"status":  "success",
-			"data":    products,
-			"message": nil,
-		}
-		c.JSON(http.StatusOK, response)
+	in, ok := listQuery(c, model.ProductFields)
+	if !ok {
+		return
 	}
+	products, page, err := model.GetProducts(in)
+	listResponse(c, "products", products, len(products), page, err)
 }
 
 //GetProduct return information from this product
@@ -56,16 +44,8 @@ func GetProduct(c *gin.Context) {
 //This route insert a product in his table
 func PostProduct(c *gin.Context) {
 	var in model.Product
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
 	//Check if client parameters are valid
-	if !model.CheckInProduct(in) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": ErrorParams,
-		}
-		c.JSON(http.StatusBadRequest, response)
+	if !bindJSON(c, &in) {
 		return
 	}
 	//As the params are correct, we proceeded
@@ -89,11 +69,143 @@ func PostProduct(c *gin.Context) {
 	}
 }
 
+//This route updates a product with an 'id', every field is replaced
+func PutProduct(c *gin.Context) {
+	var in model.Product
+	updateProduct(c, in)
+}
+
+//This route updates a product with an 'id', only fields in body are replaced
+func PatchProduct(c *gin.Context) {
+	in, err := model.GetProduct(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " product with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	updateProduct(c, in)
+}
+
+//updateProduct decodes the body over 'in' and updates the product
+func updateProduct(c *gin.Context, in model.Product) {
+	id := paramID(c, "id")
+	err := decodeJSON(c, &in)
+	checkErr(err, BindJson)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if !validRequest(c, &in) {
+		return
+	}
+	if before, err := model.GetProduct(id); err == nil {
+		auditBefore(c, before)
+	}
+	product, err := model.UpdateProduct(id, in)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " product with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    product,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route deletes a product with an 'id', it's a soft delete unless
+//'hard' query param is true, hard deletes fail if the product
+//has sales, purchases or stock movements
+func DeleteProduct(c *gin.Context) {
+	id := paramID(c, "id")
+	hard := c.Query("hard") == "true"
+	product, err := model.GetProduct(id)
+	//Soft deleted ones can be deleted permanently
+	if hard {
+		product, err = model.GetAnyProduct(id)
+	}
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " product with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	auditBefore(c, product)
+	if hard {
+		err = model.HardDeleteProduct(id)
+	} else {
+		product, err = model.DeleteProduct(id)
+	}
+	if err == model.ErrReferenced {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+	} else if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": DeleteMessageError + " a product",
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    product,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route restores a soft deleted product with an 'id'
+func RestoreProduct(c *gin.Context) {
+	product, err := model.RestoreProduct(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " deleted product with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    product,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 //GetRankProductK make route to stats model
 func GetRankProductK(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankProductK(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -102,22 +214,12 @@ func GetRankProductK(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankProductK(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -125,7 +227,10 @@ func GetRankProductK(c *gin.Context) {
 func GetSalesProductIDRec(c *gin.Context) {
 	id := c.Param("id")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	sales, err := model.GetSalesProductIDRec(id, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -134,22 +239,12 @@ func GetSalesProductIDRec(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		sales, err := model.GetSalesProductIDRec(id, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    sales,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    sales,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -158,7 +253,10 @@ func GetRankProductCategoryS(c *gin.Context) {
 	category := c.Param("category")
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankProductCategoryS(category, k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -167,22 +265,12 @@ func GetRankProductCategoryS(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankProductCategoryS(category, k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -191,7 +279,10 @@ func GetRankProductCategoryP(c *gin.Context) {
 	category := c.Param("category")
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankProductCategoryP(category, k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -200,22 +291,12 @@ func GetRankProductCategoryP(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankProductCategoryP(category, k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -224,7 +305,10 @@ func GetRankProductBrand(c *gin.Context) {
 	brand := c.Param("brand")
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankProductBrand(brand, k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -233,22 +317,12 @@ func GetRankProductBrand(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankProductBrand(brand, k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -256,7 +330,10 @@ func GetRankProductBrand(c *gin.Context) {
 func GetRankProfitability(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankProfitability(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -265,22 +342,12 @@ func GetRankProfitability(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankProfitability(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -288,7 +355,10 @@ func GetRankProfitability(c *gin.Context) {
 func GetRankProductPP(c *gin.Context) {
 	id := c.Param("id_product")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankProductPP(id, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -297,21 +367,11 @@ func GetRankProductPP(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankProductPP(id, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
@@ -7,27 +7,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-//This route asking for all providers
+//This route asking for a page of providers, see listQuery for
+//the pagination, sorting and filtering query params
 func GetProviders(c *gin.Context) {
-	//Asking to model
-	providers := model.GetProviders()
-	//If length of providers is zero,
-	//is because no exist providers
-	if checkSize(providers) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": GetMessageErrorPlural + " providers",
-		}
-		c.JSON(http.StatusNotFound, response)
-	} else {
-		response := gin.H{
-			"status":  "success",
-			"data":    providers,
-			"message": nil,
-		}
-		c.JSON(http.StatusOK, response)
+	in, ok := listQuery(c, model.AgentFields)
+	if !ok {
+		return
 	}
+	providers, page, err := model.GetProviders(in)
+	listResponse(c, "providers", providers, len(providers), page, err)
 }
 
 //This route return a provider with a 'rut'
@@ -54,16 +42,8 @@ func GetProvider(c *gin.Context) {
 //This route insert a provider in his table
 func PostProvider(c *gin.Context) {
 	var in model.Provider
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
 	//Check if provider parameters are valid
-	if !model.CheckInProvider(in) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": ErrorParams,
-		}
-		c.JSON(http.StatusBadRequest, response)
+	if !bindJSON(c, &in) {
 		return
 	}
 	//As the params are correct, we proceeded
@@ -87,11 +67,144 @@ func PostProvider(c *gin.Context) {
 	}
 }
 
+//This route updates a provider with a 'rut', every field is replaced
+func PutProvider(c *gin.Context) {
+	var in model.Provider
+	updateProvider(c, in)
+}
+
+//This route updates a provider with a 'rut', only fields in body are replaced
+func PatchProvider(c *gin.Context) {
+	in, err := model.GetProvider(c.Param("rut"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " provider with that rut",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	updateProvider(c, in)
+}
+
+//updateProvider decodes the body over 'in' and updates the provider
+func updateProvider(c *gin.Context, in model.Provider) {
+	rut := c.Param("rut")
+	err := decodeJSON(c, &in)
+	checkErr(err, BindJson)
+	//Rut is the key, it can't be changed
+	in.Rut = rut
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if !validRequest(c, &in) {
+		return
+	}
+	if before, err := model.GetProvider(rut); err == nil {
+		auditBefore(c, before)
+	}
+	provider, err := model.UpdateProvider(rut, in)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " provider with that rut",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    provider,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route deletes a provider with a 'rut', it's a soft delete unless
+//'hard' query param is true, hard deletes fail if purchases reference it
+func DeleteProvider(c *gin.Context) {
+	rut := c.Param("rut")
+	hard := c.Query("hard") == "true"
+	provider, err := model.GetProvider(rut)
+	//Soft deleted ones can be deleted permanently
+	if hard {
+		provider, err = model.GetAnyProvider(rut)
+	}
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " provider with that rut",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	auditBefore(c, provider)
+	if hard {
+		err = model.HardDeleteProvider(rut)
+	} else {
+		provider, err = model.DeleteProvider(rut)
+	}
+	if err == model.ErrReferenced {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+	} else if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": DeleteMessageError + " a provider",
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    provider,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route restores a soft deleted provider with a 'rut'
+func RestoreProvider(c *gin.Context) {
+	provider, err := model.RestoreProvider(c.Param("rut"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " deleted provider with that rut",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    provider,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 //GetRankPurchasesK make route to stats model
 func GetRankPurchasesK(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	customers, err := model.GetRankPurchasesK(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -100,22 +213,12 @@ func GetRankPurchasesK(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		customers, err := model.GetRankPurchasesK(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    customers,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    customers,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -123,7 +226,10 @@ func GetRankPurchasesK(c *gin.Context) {
 func GetRankProviderK(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankProviderK(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -132,22 +238,12 @@ func GetRankProviderK(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankProviderK(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -156,7 +252,10 @@ func GetRankProviderPP(c *gin.Context) {
 	k := c.Param("k")
 	id := c.Param("id_provider")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankProviderPP(k, id, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -165,22 +264,12 @@ func GetRankProviderPP(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankProviderPP(k, id, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -188,7 +277,10 @@ func GetRankProviderPP(c *gin.Context) {
 func GetRankProviderVariety(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankProviderVariety(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -197,21 +289,11 @@ func GetRankProviderVariety(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankProviderVariety(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
@@ -7,15 +7,25 @@ import (
 	"net/http"
 )
 
-//GetPurchase make route to model
+//This route asking for a page of purchases, see listQuery for
+//the pagination, sorting and filtering query params
+func GetPurchases(c *gin.Context) {
+	in, ok := listQuery(c, model.PurchaseFields)
+	if !ok {
+		return
+	}
+	purchases, page, err := model.GetPurchases(in)
+	listResponse(c, "purchases", purchases, len(purchases), page, err)
+}
+
+//This route return a purchase with an 'id' and its line items
 func GetPurchase(c *gin.Context) {
-	provider_id := c.Param("prov_id")
-	purchase, err := model.GetPurchase(provider_id)
+	purchase, err := model.GetPurchase(paramID(c, "id"))
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
 			"data":    nil,
-			"message": GetMessageErrorSingular + " purchase with those ID",
+			"message": GetMessageErrorSingular + " purchase with that ID",
 		}
 		c.JSON(http.StatusNotFound, response)
 	} else {
@@ -28,37 +38,36 @@ func GetPurchase(c *gin.Context) {
 	}
 }
 
-//This route insert a purchase in his table
+//This route insert a purchase with its line items ('details'),
+//the whole purchase is rejected if any line item isn't valid
 func PostPurchase(c *gin.Context) {
-	var in model.Purchase
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
+	var in model.PurchaseDoc
 	//Check if client parameters are valid
-	if err != nil {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": ErrorParams,
-		}
-		c.JSON(http.StatusBadRequest, response)
+	if !bindJSON(c, &in) {
 		return
 	}
 	//As the params are correct, we proceeded
-	//to insert input purchase
-	purchase, flag := model.InsertPurchase(&in)
-	//Flag is true if the model succeeds in inserting the client
-	if flag {
+	//to insert input purchase and its line items
+	err := model.InsertPurchaseDoc(&in)
+	if err == nil {
 		response := gin.H{
 			"status":  "success",
-			"data":    purchase,
+			"data":    in,
 			"message": nil,
 		}
 		c.JSON(http.StatusOK, response)
+	} else if err == model.ErrMissing {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
 	} else {
 		response := gin.H{
 			"status":  "error",
-			"data":    purchase,
-			"message": PostMessageError + " a client",
+			"data":    nil,
+			"message": PostMessageError + " a purchase",
 		}
 		c.JSON(http.StatusBadRequest, response)
 	}
@@ -69,7 +78,10 @@ func GetRankPurchasesCP(c *gin.Context) {
 	category := c.Param("category")
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	sales, err := model.GetRankPurchasesCP(category, k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -78,22 +90,12 @@ func GetRankPurchasesCP(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		sales, err := model.GetRankPurchasesCP(category, k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    sales,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    sales,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -101,7 +103,10 @@ func GetRankPurchasesCP(c *gin.Context) {
 func GetPurchasesProduct(c *gin.Context) {
 	id := c.Param("id_product")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	sales, err := model.GetPurchasesProduct(id, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -110,22 +115,12 @@ func GetPurchasesProduct(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		sales, err := model.GetPurchasesProduct(id, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    sales,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    sales,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -133,7 +128,10 @@ func GetPurchasesProduct(c *gin.Context) {
 func GetRankPurchasesProduct(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	sales, err := model.GetRankPurchasesProduct(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -142,21 +140,11 @@ func GetRankPurchasesProduct(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		sales, err := model.GetRankPurchasesProduct(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    sales,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    sales,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
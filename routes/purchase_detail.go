@@ -44,15 +44,7 @@ func GetPurchaseDetail(c *gin.Context) {
 //PostPurchaseDetail make route to model
 func PostPurchaseDetail(c *gin.Context) {
 	var in model.PurchaseDetail
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
-	if err != nil || !model.CheckInPurchaseDetail(in) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": ErrorParams,
-		}
-		c.JSON(http.StatusBadRequest, response)
+	if !bindJSON(c, &in) {
 		return
 	}
 
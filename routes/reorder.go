@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//Products that go below their reorder point are mailed to REORDER_MAIL
+func init() {
+	model.ReorderHook = notifyReorder
+}
+
+//This route asking for a page of the products with less stock on hand
+//than their reorder point and their suggested providers, see listQuery
+//for the pagination, sorting and filtering query params
+func GetReorders(c *gin.Context) {
+	in, ok := listQuery(c, model.ReorderFields)
+	if !ok {
+		return
+	}
+	reorders, page, err := model.GetReorders(in)
+	listResponse(c, "products to reorder", reorders, len(reorders), page, err)
+}
+
+//notifyReorder mails a product that went below its reorder point,
+//it's logged if REORDER_MAIL is empty
+func notifyReorder(reorder model.Reorder) {
+	body := fmt.Sprintf("The product %d %s has %d on hand, its reorder point is %d.\n"+
+		"Suggested quantity to purchase: %d\n",
+		reorder.ProductID, reorder.Name, reorder.OnHand, reorder.MinStock, reorder.Suggested)
+	for _, provider := range reorder.Providers {
+		body += fmt.Sprintf("\n%s, %s, %s: $%d", provider.Name, provider.Mail,
+			provider.Phone, provider.Price)
+	}
+	to := os.Getenv("REORDER_MAIL")
+	if to == "" {
+		log.Println(ReorderSubject+":", body)
+		return
+	}
+	err := sender.Send(to, ReorderSubject, body)
+	checkErr(err, ReorderMailError)
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"github.com/fabulias/coimco_backend/auth"
 	"github.com/fabulias/coimco_backend/model"
 	"github.com/gin-gonic/gin"
 
@@ -10,16 +11,18 @@ import (
 
 //GetSalesID bind JSON, param URI inputs and call model stats
 func GetSalesID(c *gin.Context) {
-	mail := c.Param("mail")
+	mail := sellerScope(c, "mail")
 	var in model.Date
-	err := c.BindJSON(&in)
-	if strings.Compare(mail, "") == 0 || err != nil {
+	if !bindJSON(c, &in) {
+		return
+	}
+	if strings.Compare(mail, "") == 0 {
 		response := gin.H{
 			"status":  "error",
 			"data":    nil,
-			"message": BindJson,
+			"message": ErrorParams,
 		}
-		c.JSON(http.StatusNotFound, response)
+		c.JSON(http.StatusBadRequest, response)
 	} else {
 		res, err := model.GetSalesID(mail, in)
 		if err != nil {
@@ -43,46 +46,57 @@ func GetSalesID(c *gin.Context) {
 //GetSales bind JSON input and call model stats
 func GetSales(c *gin.Context) {
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	//Asking to model
+	res, err := model.GetSales(in)
+	//If length of sales is zero,
+	//is because no exist sales
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
 			"data":    nil,
-			"message": BindJson,
+			"message": GetMessageErrorPlural + " sales",
 		}
 		c.JSON(http.StatusNotFound, response)
 	} else {
-		//Asking to model
-		res, err := model.GetSales(in)
-		//If length of sales is zero,
-		//is because no exist sales
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": GetMessageErrorPlural + " sales",
-			}
-			c.JSON(http.StatusNotFound, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    res,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    res,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
+//This route asking for a page of sales with their amounts, see listQuery
+//for the pagination, sorting and filtering query params.
+//Sellers only see their own sales
+func GetSaleSummaries(c *gin.Context) {
+	in, ok := listQuery(c, model.SaleFields)
+	if !ok {
+		return
+	}
+	if auth.Role(c) == model.SELLER {
+		in.Filters["user_id"] = auth.Mail(c)
+	}
+	sales, page, err := model.GetSaleSummaries(in)
+	listResponse(c, "sales", sales, len(sales), page, err)
+}
+
+//This route return a sale with an 'id', its line items and amounts.
+//Sellers only see their own sales
 func GetSale(c *gin.Context) {
-	customer_id := c.Param("cus_id")
-	user_id := c.Param("user_id")
-	sale, err := model.GetSale(customer_id, user_id)
+	sale, err := model.GetSale(paramID(c, "id"))
+	if err == nil && auth.Role(c) == model.SELLER && sale.UserID != auth.Mail(c) {
+		err = model.ErrMissing
+	}
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
 			"data":    nil,
-			"message": GetMessageErrorSingular + " sale with those ID",
+			"message": GetMessageErrorSingular + " sale with that ID",
 		}
 		c.JSON(http.StatusNotFound, response)
 	} else {
@@ -95,12 +109,12 @@ func GetSale(c *gin.Context) {
 	}
 }
 
-//This route insert a sale in his table
+//This route insert a sale with its line items ('details'),
+//the whole sale is rejected if any line item isn't valid
 func PostSale(c *gin.Context) {
-	var in model.Sale
-	err := c.BindJSON(&in)
+	var in model.SaleDoc
+	err := decodeJSON(c, &in)
 	checkErr(err, BindJson)
-	//Check if client parameters are valid
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -110,21 +124,42 @@ func PostSale(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, response)
 		return
 	}
+	//Sellers always sell on their own account
+	if auth.Role(c) == model.SELLER {
+		in.UserID = auth.Mail(c)
+	}
+	//Check if client parameters are valid
+	if !validRequest(c, &in) {
+		return
+	}
 	//As the params are correct, we proceeded
-	//to insert input sale
-	sale, flag := model.InsertSale(&in)
-	//Flag is true if the model succeeds in inserting the client
-	if flag {
+	//to insert input sale and its line items
+	err = model.InsertSaleDoc(&in)
+	if err == model.ErrMissing {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else if _, ok := err.(model.StockError); ok {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+	} else if err == nil {
 		response := gin.H{
 			"status":  "success",
-			"data":    sale,
+			"data":    in,
 			"message": nil,
 		}
 		c.JSON(http.StatusOK, response)
 	} else {
 		response := gin.H{
 			"status":  "error",
-			"data":    sale,
+			"data":    nil,
 			"message": PostMessageError + " a sale",
 		}
 		c.JSON(http.StatusBadRequest, response)
@@ -135,7 +170,10 @@ func PostSale(c *gin.Context) {
 func GetRankSalesK(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankSalesK(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -144,22 +182,12 @@ func GetRankSalesK(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankSalesK(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -168,7 +196,10 @@ func GetRankSalesCategory(c *gin.Context) {
 	k := c.Param("k")
 	category := c.Param("category")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankSalesCategory(k, category, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -177,22 +208,12 @@ func GetRankSalesCategory(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankSalesCategory(k, category, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -200,7 +221,10 @@ func GetRankSalesCategory(c *gin.Context) {
 func GetRankSalesProduct(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankSalesProduct(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -209,22 +233,12 @@ func GetRankSalesProduct(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankSalesProduct(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -232,7 +246,10 @@ func GetRankSalesProduct(c *gin.Context) {
 func GetRankSalesArea(c *gin.Context) {
 	k := c.Param("k")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetRankSalesArea(k, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -241,22 +258,12 @@ func GetRankSalesArea(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetRankSalesArea(k, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -264,7 +271,10 @@ func GetRankSalesArea(c *gin.Context) {
 func GetSalesProduct(c *gin.Context) {
 	id := c.Param("id_product")
 	var in model.Date
-	err := c.BindJSON(&in)
+	if !bindJSON(c, &in) {
+		return
+	}
+	products, err := model.GetSalesProduct(id, in)
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -273,21 +283,11 @@ func GetSalesProduct(c *gin.Context) {
 		}
 		c.JSON(http.StatusBadRequest, response)
 	} else {
-		products, err := model.GetSalesProduct(id, in)
-		if err != nil {
-			response := gin.H{
-				"status":  "error",
-				"data":    nil,
-				"message": err.Error(),
-			}
-			c.JSON(http.StatusBadRequest, response)
-		} else {
-			response := gin.H{
-				"status":  "success",
-				"data":    products,
-				"message": nil,
-			}
-			c.JSON(http.StatusOK, response)
+		response := gin.H{
+			"status":  "success",
+			"data":    products,
+			"message": nil,
 		}
+		c.JSON(http.StatusOK, response)
 	}
 }
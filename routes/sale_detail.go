@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/fabulias/coimco_backend/auth"
 	"github.com/fabulias/coimco_backend/model"
 	"github.com/gin-gonic/gin"
 )
@@ -23,7 +24,15 @@ func GetSaleDetail(c *gin.Context) {
 	}
 	s_id, _ := strconv.ParseUint(sale_id, 10, 32)
 	pro_id, _ := strconv.ParseUint(product_id, 10, 32)
-	sale_detail, err := model.GetSaleDetail(uint(s_id), uint(pro_id))
+	//Sellers only see the lines of their own sales
+	sale, err := model.GetSale(uint(s_id))
+	if err == nil && auth.Role(c) == model.SELLER && sale.UserID != auth.Mail(c) {
+		err = model.ErrMissing
+	}
+	var sale_detail model.SaleDetail
+	if err == nil {
+		sale_detail, err = model.GetSaleDetail(uint(s_id), uint(pro_id))
+	}
 	if err != nil {
 		response := gin.H{
 			"status":  "error",
@@ -44,21 +53,34 @@ func GetSaleDetail(c *gin.Context) {
 //PostSaleDetail makes route to model
 func PostSaleDetail(c *gin.Context) {
 	var in model.SaleDetail
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
-	if err != nil || !model.CheckInSaleDetail(in) {
+	if !bindJSON(c, &in) {
+		return
+	}
+	//Sellers only add lines to their own sales
+	sale, err := model.GetSale(in.SaleID)
+	if err == nil && auth.Role(c) == model.SELLER && sale.UserID != auth.Mail(c) {
+		err = model.ErrMissing
+	}
+	if err != nil {
 		response := gin.H{
 			"status":  "error",
 			"data":    nil,
-			"message": ErrorParams,
+			"message": GetMessageErrorSingular + " sale with that ID",
 		}
-		c.JSON(http.StatusBadRequest, response)
+		c.JSON(http.StatusNotFound, response)
 		return
 	}
 
-	sale_detail, flag := model.InsertSaleDetail(&in)
+	sale_detail, err := model.InsertSaleDetail(&in)
 
-	if flag {
+	if _, ok := err.(model.StockError); ok {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+	} else if err == nil {
 		response := gin.H{
 			"status":  "success",
 			"data":    sale_detail,
@@ -11,9 +11,11 @@ import (
 func GetRankSellerProductK(c *gin.Context) {
 	var in model.Date
 	k := c.Param("k")
-	seller := c.Param("seller")
-	err := c.BindJSON(&in)
-	if err != nil || k == "" || seller == "" {
+	seller := sellerScope(c, "seller")
+	if !bindJSON(c, &in) {
+		return
+	}
+	if k == "" || seller == "" {
 		resp := gin.H{
 			"status":  "error",
 			"data":    nil,
@@ -47,10 +49,12 @@ func GetRankSellerProductK(c *gin.Context) {
 func GetRankSellerProductC(c *gin.Context) {
 	var in model.Date
 	k := c.Param("k")
-	seller := c.Param("seller")
+	seller := sellerScope(c, "seller")
 	category := c.Param("category")
-	err := c.BindJSON(&in)
-	if err != nil || k == "" || category == "" || seller == "" {
+	if !bindJSON(c, &in) {
+		return
+	}
+	if k == "" || category == "" || seller == "" {
 		resp := gin.H{
 			"status":  "error",
 			"data":    nil,
@@ -82,10 +86,12 @@ func GetRankSellerProductC(c *gin.Context) {
 func GetRankSellerProductB(c *gin.Context) {
 	var in model.Date
 	k := c.Param("k")
-	seller := c.Param("seller")
+	seller := sellerScope(c, "seller")
 	brand := c.Param("brand")
-	err := c.BindJSON(&in)
-	if err != nil || k == "" || brand == "" || seller == "" {
+	if !bindJSON(c, &in) {
+		return
+	}
+	if k == "" || brand == "" || seller == "" {
 		resp := gin.H{
 			"status":  "error",
 			"data":    nil,
@@ -117,9 +123,11 @@ func GetRankSellerProductB(c *gin.Context) {
 func GetRankSellerCustomerK(c *gin.Context) {
 	var in model.Date
 	k := c.Param("k")
-	seller := c.Param("seller")
-	err := c.BindJSON(&in)
-	if err != nil || k == "" || seller == "" {
+	seller := sellerScope(c, "seller")
+	if !bindJSON(c, &in) {
+		return
+	}
+	if k == "" || seller == "" {
 		resp := gin.H{
 			"status":  "error",
 			"data":    nil,
@@ -151,10 +159,12 @@ func GetRankSellerCustomerK(c *gin.Context) {
 func GetRankSellerCustomerP(c *gin.Context) {
 	var in model.Date
 	k := c.Param("k")
-	seller := c.Param("seller")
+	seller := sellerScope(c, "seller")
 	id := c.Param("id_customer")
-	err := c.BindJSON(&in)
-	if err != nil || k == "" || seller == "" || id == "" {
+	if !bindJSON(c, &in) {
+		return
+	}
+	if k == "" || seller == "" || id == "" {
 		resp := gin.H{
 			"status":  "error",
 			"data":    nil,
@@ -186,10 +196,12 @@ func GetRankSellerCustomerP(c *gin.Context) {
 func GetRankSellerCustomerL(c *gin.Context) {
 	var in model.Date
 	k := c.Param("k")
-	seller := c.Param("seller")
+	seller := sellerScope(c, "seller")
 	l := c.Param("l")
-	err := c.BindJSON(&in)
-	if err != nil || k == "" || seller == "" || l == "" {
+	if !bindJSON(c, &in) {
+		return
+	}
+	if k == "" || seller == "" || l == "" {
 		resp := gin.H{
 			"status":  "error",
 			"data":    nil,
@@ -221,9 +233,11 @@ func GetRankSellerCustomerL(c *gin.Context) {
 func GetRankSellerSalesK(c *gin.Context) {
 	var in model.Date
 	k := c.Param("k")
-	seller := c.Param("seller")
-	err := c.BindJSON(&in)
-	if err != nil || k == "" || seller == "" {
+	seller := sellerScope(c, "seller")
+	if !bindJSON(c, &in) {
+		return
+	}
+	if k == "" || seller == "" {
 		resp := gin.H{
 			"status":  "error",
 			"data":    nil,
@@ -255,10 +269,12 @@ func GetRankSellerSalesK(c *gin.Context) {
 func GetRankSellerSalesC(c *gin.Context) {
 	var in model.Date
 	k := c.Param("k")
-	seller := c.Param("seller")
+	seller := sellerScope(c, "seller")
 	category := c.Param("category")
-	err := c.BindJSON(&in)
-	if err != nil || k == "" || seller == "" || category == "" {
+	if !bindJSON(c, &in) {
+		return
+	}
+	if k == "" || seller == "" || category == "" {
 		resp := gin.H{
 			"status":  "error",
 			"data":    nil,
@@ -290,9 +306,11 @@ func GetRankSellerSalesC(c *gin.Context) {
 func GetRankSellerSalesP(c *gin.Context) {
 	var in model.Date
 	k := c.Param("k")
-	seller := c.Param("seller")
-	err := c.BindJSON(&in)
-	if err != nil || k == "" || seller == "" {
+	seller := sellerScope(c, "seller")
+	if !bindJSON(c, &in) {
+		return
+	}
+	if k == "" || seller == "" {
 		resp := gin.H{
 			"status":  "error",
 			"data":    nil,
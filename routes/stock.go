@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/fabulias/coimco_backend/auth"
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/fabulias/coimco_backend/validation"
+	"github.com/gin-gonic/gin"
+)
+
+//This route asking for a page of the stock on hand of products, see
+//listQuery for the pagination, sorting and filtering query params
+func GetStocks(c *gin.Context) {
+	in, ok := listQuery(c, model.StockFields)
+	if !ok {
+		return
+	}
+	stocks, page, err := model.GetStocks(in)
+	listResponse(c, "products in stock", stocks, len(stocks), page, err)
+}
+
+//This route return the stock on hand of a product with a 'product_id'
+func GetStock(c *gin.Context) {
+	stock, err := model.GetStock(paramID(c, "product_id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " product with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    stock,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route asking for a page of the stock movements of a product
+//with a 'product_id', see listQuery for the query params
+func GetStockMovements(c *gin.Context) {
+	in, ok := listQuery(c, model.StockMovementFields)
+	if !ok {
+		return
+	}
+	movements, page, err := model.GetStockMovements(paramID(c, "product_id"), in)
+	listResponse(c, "stock movements", movements, len(movements), page, err)
+}
+
+//This route insert an adjustment or return stock movement
+func PostStockMovement(c *gin.Context) {
+	var in model.StockMovementInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	//Returned products only come in
+	if in.Kind == model.MoveReturn && in.Quantity < 0 {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ValidationError,
+			"errors": validation.Errors{{
+				Field:   "quantity",
+				Rule:    "gt",
+				Message: "must be greater than 0",
+			}},
+		}
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+	movement, err := model.InsertStockMovement(in, auth.Mail(c))
+	if err == model.ErrMissing {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": PostMessageError + " a stock movement",
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    movement,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
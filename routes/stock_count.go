@@ -0,0 +1,142 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/fabulias/coimco_backend/auth"
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//This route asking for a page of stock counts, see listQuery for
+//the pagination, sorting and filtering query params
+func GetStockCounts(c *gin.Context) {
+	in, ok := listQuery(c, model.StockCountFields)
+	if !ok {
+		return
+	}
+	counts, page, err := model.GetStockCounts(in)
+	listResponse(c, "stock counts", counts, len(counts), page, err)
+}
+
+//This route return a stock count with an 'id' and its lines
+func GetStockCount(c *gin.Context) {
+	doc, err := model.GetStockCount(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " stock count with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    doc,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route return the variance report of a stock count with an 'id'
+func GetStockCountReport(c *gin.Context) {
+	report, err := model.GetStockCountReport(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " stock count with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    report,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route opens a stock count
+func PostStockCount(c *gin.Context) {
+	var in model.StockCount
+	if !bindJSON(c, &in) {
+		return
+	}
+	err := model.InsertStockCount(&in, auth.Mail(c))
+	if err == model.ErrMissing {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": PostMessageError + " a stock count",
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    in,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route records counted quantities in an open stock count with an 'id'
+func PostStockCountLines(c *gin.Context) {
+	var in model.StockCountInput
+	if !bindJSON(c, &in) {
+		return
+	}
+	doc, err := model.InsertStockCountLines(paramID(c, "id"), in, auth.Mail(c))
+	stockCountResponse(c, doc, err, PostMessageError+" the stock count lines")
+}
+
+//This route closes an open stock count with an 'id',
+//adjusting the stock of the products with differences
+func CloseStockCount(c *gin.Context) {
+	doc, err := model.CloseStockCount(paramID(c, "id"), auth.Mail(c))
+	stockCountResponse(c, doc, err, PostMessageError+" the stock count adjustments")
+}
+
+//stockCountResponse responds a stock count after it's written,
+//'message' is used when it fails
+func stockCountResponse(c *gin.Context, doc model.StockCountDoc, err error, message string) {
+	if err == model.ErrClosed {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+	} else if err == model.ErrMissing {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": message,
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    doc,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
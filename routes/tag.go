@@ -8,20 +8,22 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+//This route asking for a page of tags, see listQuery for
+//the pagination, sorting and filtering query params
+func GetTags(c *gin.Context) {
+	in, ok := listQuery(c, model.TagFields)
+	if !ok {
+		return
+	}
+	tags, page, err := model.GetTags(in)
+	listResponse(c, "tags", tags, len(tags), page, err)
+}
+
 //This route insert a product in his table
 func PostTag(c *gin.Context) {
 	var in model.Tag
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
 	//Check if client parameters are valid
-	if model.CheckInTag(in) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": ErrorParams,
-		}
-		c.JSON(http.StatusBadRequest, response)
-		c.AbortWithStatus(http.StatusBadRequest)
+	if !bindJSON(c, &in) {
 		return
 	}
 	product, flag := model.InsertTag(&in)
@@ -64,3 +66,131 @@ func GetTag(c *gin.Context) {
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+//This route updates a tag with an 'id', every field is replaced
+func PutTag(c *gin.Context) {
+	var in model.Tag
+	updateTag(c, in)
+}
+
+//This route updates a tag with an 'id', only fields in body are replaced
+func PatchTag(c *gin.Context) {
+	in, err := model.GetTag(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " tag with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	updateTag(c, in)
+}
+
+//updateTag decodes the body over 'in' and updates the tag
+func updateTag(c *gin.Context, in model.Tag) {
+	id := paramID(c, "id")
+	err := decodeJSON(c, &in)
+	checkErr(err, BindJson)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if !validRequest(c, &in) {
+		return
+	}
+	if before, err := model.GetTag(id); err == nil {
+		auditBefore(c, before)
+	}
+	tag, err := model.UpdateTag(id, in)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " tag with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    tag,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route deletes a tag with an 'id', it's a soft delete unless
+//'hard' query param is true, hard deletes fail if customers reference it
+func DeleteTag(c *gin.Context) {
+	id := paramID(c, "id")
+	hard := c.Query("hard") == "true"
+	tag, err := model.GetTag(id)
+	//Soft deleted ones can be deleted permanently
+	if hard {
+		tag, err = model.GetAnyTag(id)
+	}
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " tag with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	auditBefore(c, tag)
+	if hard {
+		err = model.HardDeleteTag(id)
+	} else {
+		tag, err = model.DeleteTag(id)
+	}
+	if err == model.ErrReferenced {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+	} else if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": DeleteMessageError + " a tag",
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    tag,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route restores a soft deleted tag with an 'id'
+func RestoreTag(c *gin.Context) {
+	tag, err := model.RestoreTag(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " deleted tag with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    tag,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -1,7 +1,6 @@
 package routes
 
 import (
-	"log"
 	"net/http"
 
 	"github.com/fabulias/coimco_backend/model"
@@ -11,18 +10,8 @@ import (
 //PostTagCustomer makes route to model
 func PostTagCustomer(c *gin.Context) {
 	var in model.TagCustomer
-	err := c.BindJSON(&in)
-	checkErr(err, BindJson)
-	log.Println("in-> ", in)
 	//Check if tag parameters are valid
-	if model.CheckInTagCustomer(in) {
-		response := gin.H{
-			"status":  "error",
-			"data":    nil,
-			"message": ErrorParams,
-		}
-		c.JSON(http.StatusBadRequest, response)
-		c.AbortWithStatus(http.StatusBadRequest)
+	if !bindJSON(c, &in) {
 		return
 	}
 	tag, flag := model.InsertTagCustomer(&in)
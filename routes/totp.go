@@ -0,0 +1,181 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/fabulias/coimco_backend/auth"
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//LoginTOTP finishes a sign in with the second factor
+func LoginTOTP(c *gin.Context) {
+	var in model.Challenge
+	if !bindJSON(c, &in) {
+		return
+	}
+	if checkSize(in.Code) {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	acc, err := auth.ChallengeAccount(in.Challenge)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	//Codes are short, so they are protected like passwords
+	if loginLocked(c, acc.Mail) {
+		return
+	}
+	codes, err := auth.VerifyChallenge(acc, in.Code)
+	loginAttempt(c, acc.Mail, err == nil)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	var extra gin.H
+	if codes != nil {
+		extra = gin.H{"recovery_codes": codes}
+	}
+	loginSuccess(c, acc, extra)
+}
+
+//LoginTOTPEnroll starts the second factor enrollment of an account
+//that must use it while signing in
+func LoginTOTPEnroll(c *gin.Context) {
+	var in model.Challenge
+	if !bindJSON(c, &in) {
+		return
+	}
+	secret, uri, err := auth.EnrollChallenge(in.Challenge)
+	totpEnrollResponse(c, secret, uri, err)
+}
+
+//PostMyTOTP starts the second factor enrollment of the account
+//that made the request
+func PostMyTOTP(c *gin.Context) {
+	//The secret can't be saved in the audit log
+	auditHide(c)
+	acc, err := model.GetAccount(auth.Mail(c))
+	var secret, uri string
+	if err == nil {
+		secret, uri, err = auth.EnrollTOTP(acc)
+	}
+	totpEnrollResponse(c, secret, uri, err)
+}
+
+//totpEnrollResponse writes the secret and provisioning URI of an enrollment
+func totpEnrollResponse(c *gin.Context, secret, uri string, err error) {
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	data := gin.H{
+		"secret": secret,
+		"uri":    uri,
+	}
+	response := gin.H{
+		"status":  "success",
+		"data":    data,
+		"message": TOTPEnrollOK,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+//PostMyTOTPConfirm enables the second factor with a code of the app
+func PostMyTOTPConfirm(c *gin.Context) {
+	//The recovery codes can't be saved in the audit log
+	auditHide(c)
+	var in model.TOTPCode
+	if !bindJSON(c, &in) {
+		return
+	}
+	acc, err := model.GetAccount(auth.Mail(c))
+	var codes []string
+	if err == nil {
+		codes, err = auth.ConfirmTOTP(acc, in.Code)
+	}
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	data := gin.H{
+		"recovery_codes": codes,
+	}
+	response := gin.H{
+		"status":  "success",
+		"data":    data,
+		"message": TOTPConfirmOK,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+//DeleteMyTOTP disables the second factor of the account that made the request
+func DeleteMyTOTP(c *gin.Context) {
+	var in model.TOTPCode
+	if !bindJSON(c, &in) {
+		return
+	}
+	acc, err := model.GetAccount(auth.Mail(c))
+	if err == nil {
+		err = auth.DisableTOTP(acc, in.Code)
+	}
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	response := gin.H{
+		"status":  "success",
+		"data":    nil,
+		"message": TOTPDisableOK,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+//DeleteAccountTOTP removes the second factor of an account
+//that lost its device, the account must enroll again
+func DeleteAccountTOTP(c *gin.Context) {
+	mail := c.Param("mail")
+	account, err := model.GetAccount(mail)
+	if err == nil {
+		auditBefore(c, account)
+		err = model.DisableTOTP(mail)
+	}
+	if err == nil {
+		err = model.RevokeRefreshTokens(mail)
+	}
+	if err == nil {
+		account, err = model.GetAccount(mail)
+	}
+	accountResponse(c, account, err)
+}
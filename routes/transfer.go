@@ -0,0 +1,79 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/fabulias/coimco_backend/auth"
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//This route asking for a page of transfers, see listQuery for
+//the pagination, sorting and filtering query params
+func GetTransfers(c *gin.Context) {
+	in, ok := listQuery(c, model.TransferFields)
+	if !ok {
+		return
+	}
+	transfers, page, err := model.GetTransfers(in)
+	listResponse(c, "transfers", transfers, len(transfers), page, err)
+}
+
+//This route return a transfer with an 'id' and its line items
+func GetTransfer(c *gin.Context) {
+	doc, err := model.GetTransfer(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " transfer with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    doc,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route insert a transfer and its line items, moving
+//the stock from a warehouse to another
+func PostTransfer(c *gin.Context) {
+	var in model.TransferDoc
+	if !bindJSON(c, &in) {
+		return
+	}
+	err := model.InsertTransferDoc(&in, auth.Mail(c))
+	if err == model.ErrMissing {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else if _, ok := err.(model.StockError); ok {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+	} else if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": PostMessageError + " a transfer",
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    in,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -1,11 +1,24 @@
 package routes
 
 import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/fabulias/coimco_backend/auth"
 	"github.com/fabulias/coimco_backend/model"
+	"github.com/fabulias/coimco_backend/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"log"
 	"strings"
 )
 
+//Requests bound by gin are validated like validRequest does
+func init() {
+	binding.Validator = validation.StructValidator{}
+}
+
 //Check error function
 func checkErr(err error, msg string) {
 	if err != nil {
@@ -13,6 +26,65 @@ func checkErr(err error, msg string) {
 	}
 }
 
+//sellerScope return the seller mail of the URI param,
+//sellers are always scoped to their own mail
+func sellerScope(c *gin.Context, param string) string {
+	if auth.Role(c) == model.SELLER {
+		return auth.Mail(c)
+	}
+	return c.Param(param)
+}
+
+//decodeJSON decodes the request body over 'obj' without the binding
+//validation, fields missing in the body keep their values
+func decodeJSON(c *gin.Context, obj interface{}) error {
+	return json.NewDecoder(c.Request.Body).Decode(obj)
+}
+
+//bindJSON decodes the request body over 'obj' and validates it,
+//it responds 400 if the body isn't JSON and return false
+//if it isn't valid, see validRequest
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	err := decodeJSON(c, obj)
+	if err != nil {
+		checkErr(err, BindJson)
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return false
+	}
+	return validRequest(c, obj)
+}
+
+//validRequest validates 'obj' by its binding tags,
+//it responds 422 with the field errors and return false if it isn't valid
+func validRequest(c *gin.Context, obj interface{}) bool {
+	errs := validation.Struct(obj)
+	if errs == nil {
+		return true
+	}
+	response := gin.H{
+		"status":  "error",
+		"data":    nil,
+		"message": ValidationError,
+		"errors":  errs,
+	}
+	c.JSON(http.StatusUnprocessableEntity, response)
+	return false
+}
+
+//paramID return the URI param as an ID, zero if it isn't valid
+func paramID(c *gin.Context, param string) uint {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
+
 //checkSize return a state of length in arrays.
 func checkSize(sample interface{}) bool {
 	var flag bool = false
@@ -0,0 +1,193 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/fabulias/coimco_backend/model"
+	"github.com/gin-gonic/gin"
+)
+
+//This route asking for a page of warehouses, see listQuery for
+//the pagination, sorting and filtering query params
+func GetWarehouses(c *gin.Context) {
+	in, ok := listQuery(c, model.WarehouseFields)
+	if !ok {
+		return
+	}
+	warehouses, page, err := model.GetWarehouses(in)
+	listResponse(c, "warehouses", warehouses, len(warehouses), page, err)
+}
+
+//This route return a warehouse with an 'id'
+func GetWarehouse(c *gin.Context) {
+	warehouse, err := model.GetWarehouse(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " warehouse with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    warehouse,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route asking for a page of the stock on hand of products in a
+//warehouse with an 'id', see listQuery for the query params
+func GetWarehouseStocks(c *gin.Context) {
+	in, ok := listQuery(c, model.WarehouseStockFields)
+	if !ok {
+		return
+	}
+	stocks, page, err := model.GetWarehouseStocks(paramID(c, "id"), in)
+	listResponse(c, "products in stock", stocks, len(stocks), page, err)
+}
+
+//This route insert a warehouse in his table
+func PostWarehouse(c *gin.Context) {
+	var in model.Warehouse
+	if !bindJSON(c, &in) {
+		return
+	}
+	warehouse, flag := model.InsertWarehouse(&in)
+	if flag {
+		response := gin.H{
+			"status":  "success",
+			"data":    warehouse,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	} else {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": PostMessageError + " a warehouse",
+		}
+		c.JSON(http.StatusBadRequest, response)
+	}
+}
+
+//This route updates a warehouse with an 'id', every field is replaced
+func PutWarehouse(c *gin.Context) {
+	var in model.Warehouse
+	updateWarehouse(c, in)
+}
+
+//This route updates a warehouse with an 'id', only fields in body are replaced
+func PatchWarehouse(c *gin.Context) {
+	in, err := model.GetWarehouse(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " warehouse with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	updateWarehouse(c, in)
+}
+
+//updateWarehouse decodes the body over 'in' and updates the warehouse
+func updateWarehouse(c *gin.Context, in model.Warehouse) {
+	id := paramID(c, "id")
+	err := decodeJSON(c, &in)
+	checkErr(err, BindJson)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": ErrorParams,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if !validRequest(c, &in) {
+		return
+	}
+	if before, err := model.GetWarehouse(id); err == nil {
+		auditBefore(c, before)
+	}
+	warehouse, err := model.UpdateWarehouse(id, in)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " warehouse with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    warehouse,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route soft deletes a warehouse with an 'id', it fails
+//if it's the default warehouse or it has stock on hand
+func DeleteWarehouse(c *gin.Context) {
+	id := paramID(c, "id")
+	warehouse, err := model.GetWarehouse(id)
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " warehouse with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	auditBefore(c, warehouse)
+	warehouse, err = model.DeleteWarehouse(id)
+	if err == model.ErrReferenced {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+	} else if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": DeleteMessageError + " a warehouse",
+		}
+		c.JSON(http.StatusBadRequest, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    warehouse,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+//This route restores a soft deleted warehouse with an 'id'
+func RestoreWarehouse(c *gin.Context) {
+	warehouse, err := model.RestoreWarehouse(paramID(c, "id"))
+	if err != nil {
+		response := gin.H{
+			"status":  "error",
+			"data":    nil,
+			"message": GetMessageErrorSingular + " deleted warehouse with that ID",
+		}
+		c.JSON(http.StatusNotFound, response)
+	} else {
+		response := gin.H{
+			"status":  "success",
+			"data":    warehouse,
+			"message": nil,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,89 @@
+//Package rut validates and formats Chilean RUTs (Rol Único Tributario)
+package rut
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//ErrInvalid is returned when a RUT is malformed or its check digit is wrong
+var ErrInvalid = errors.New("The RUT isn't valid")
+
+//clean removes dots, dashes and spaces, and uppercases the check digit
+func clean(rut string) string {
+	replacer := strings.NewReplacer(".", "", "-", "", " ", "")
+	return strings.ToUpper(replacer.Replace(rut))
+}
+
+//split return the number and check digit of a RUT
+func split(rut string) (string, string, error) {
+	rut = clean(rut)
+	if len(rut) < 2 {
+		return "", "", ErrInvalid
+	}
+	number, digit := strings.TrimLeft(rut[:len(rut)-1], "0"), rut[len(rut)-1:]
+	if number == "" || len(number) > 9 {
+		return "", "", ErrInvalid
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return "", "", ErrInvalid
+		}
+	}
+	if CheckDigit(number) != digit {
+		return "", "", ErrInvalid
+	}
+	return number, digit, nil
+}
+
+//CheckDigit return the módulo 11 check digit of the number of a RUT,
+//the number must contain only digits
+func CheckDigit(number string) string {
+	sum, factor := 0, 2
+	for i := len(number) - 1; i >= 0; i-- {
+		sum += int(number[i]-'0') * factor
+		factor++
+		if factor > 7 {
+			factor = 2
+		}
+	}
+	switch digit := 11 - sum%11; digit {
+	case 11:
+		return "0"
+	case 10:
+		return "K"
+	default:
+		return strconv.Itoa(digit)
+	}
+}
+
+//Valid return true if the RUT is well formed and its check digit is right,
+//dots and dash are optional
+func Valid(rut string) bool {
+	_, _, err := split(rut)
+	return err == nil
+}
+
+//Normalize return the RUT as it's stored, without dots
+//and with dash, e.g. "12345678-5"
+func Normalize(rut string) (string, error) {
+	number, digit, err := split(rut)
+	if err != nil {
+		return "", err
+	}
+	return number + "-" + digit, nil
+}
+
+//Format return the RUT as it's displayed, with dots
+//and dash, e.g. "12.345.678-5"
+func Format(rut string) (string, error) {
+	number, digit, err := split(rut)
+	if err != nil {
+		return "", err
+	}
+	for i := len(number) - 3; i > 0; i -= 3 {
+		number = number[:i] + "." + number[i:]
+	}
+	return number + "-" + digit, nil
+}
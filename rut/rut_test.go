@@ -0,0 +1,97 @@
+package rut
+
+import "testing"
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		number string
+		digit  string
+	}{
+		{"12345678", "5"},
+		{"10000013", "K"},
+		{"10000004", "0"},
+		{"1", "9"},
+	}
+	for _, test := range tests {
+		if digit := CheckDigit(test.number); digit != test.digit {
+			t.Errorf("CheckDigit(%q) = %q, want %q", test.number, digit, test.digit)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		rut   string
+		valid bool
+	}{
+		{"12345678-5", true},
+		{"12.345.678-5", true},
+		{"123456785", true},
+		{" 12.345.678-5 ", true},
+		{"10000013-K", true},
+		{"10000013-k", true},
+		{"10.000.004-0", true},
+		{"012345678-5", true},
+		{"12345678-4", false},
+		{"10000013-0", false},
+		{"12A45678-5", false},
+		{"abcdefgh-K", false},
+		{"1234567890-1", false},
+		{"-5", false},
+		{"5", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if valid := Valid(test.rut); valid != test.valid {
+			t.Errorf("Valid(%q) = %v, want %v", test.rut, valid, test.valid)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		rut  string
+		want string
+		err  error
+	}{
+		{"12.345.678-5", "12345678-5", nil},
+		{"123456785", "12345678-5", nil},
+		{"10.000.013-k", "10000013-K", nil},
+		{"00012345678-5", "12345678-5", nil},
+		{"12.345.678-6", "", ErrInvalid},
+		{"", "", ErrInvalid},
+	}
+	for _, test := range tests {
+		got, err := Normalize(test.rut)
+		if got != test.want || err != test.err {
+			t.Errorf("Normalize(%q) = %q, %v, want %q, %v",
+				test.rut, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		rut  string
+		want string
+		err  error
+	}{
+		{"123456785", "12.345.678-5", nil},
+		{"12345678-5", "12.345.678-5", nil},
+		{"12.345.678-5", "12.345.678-5", nil},
+		{"10000013-k", "10.000.013-K", nil},
+		{"1234567-4", "1.234.567-4", nil},
+		{"123456-0", "123.456-0", nil},
+		{"1-9", "1-9", nil},
+		{"123456789-2", "123.456.789-2", nil},
+		{"12345678-4", "", ErrInvalid},
+		{"", "", ErrInvalid},
+	}
+	for _, test := range tests {
+		got, err := Format(test.rut)
+		if got != test.want || err != test.err {
+			t.Errorf("Format(%q) = %q, %v, want %q, %v",
+				test.rut, got, err, test.want, test.err)
+		}
+	}
+}
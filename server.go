@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/fabulias/coimco_backend/auth"
+	"github.com/fabulias/coimco_backend/model"
 	"github.com/fabulias/coimco_backend/routes"
 	"github.com/gin-gonic/gin"
 )
@@ -45,6 +46,9 @@ func main() {
 	}
 
 	r := gin.New()
+	//The client IP of sign in lockouts is only taken from X-Real-Ip and
+	//X-Forwarded-For when a trusted proxy sets them
+	r.ForwardedByClientIP = os.Getenv("TRUSTED_PROXY") == "true"
 
 	// Global middleware
 	r.Use(gin.Logger())
@@ -52,75 +56,57 @@ func main() {
 
 	r.Use(Cors())
 	r.POST("/login", routes.Login)
+	r.POST("/login/totp", routes.LoginTOTP)
+	r.POST("/login/totp/enroll", routes.LoginTOTPEnroll)
+	r.POST("/token/refresh", routes.RefreshToken)
+	r.POST("/logout", auth.ValidateToken(), routes.Logout)
+	r.POST("/password/forgot", routes.ForgotPassword)
+	r.POST("/password/reset", routes.Audit("account"), routes.ResetPassword)
+	// Authenticated routes, by token or API key
+	api := r.Group("api")
+	api.Use(auth.ValidateToken())
+
 	// Simple group: v1
-	v1 := r.Group("api")
-	v1.Use(auth.ValidateToken())
+	v1 := api.Group("")
+	v1.Use(auth.RequireRole(model.ADMIN, model.MANAGER, model.SELLER))
 	{
 		//Methods plurals GET
 		v1.GET("/customers", routes.GetCustomers)
 		v1.GET("/products", routes.GetProducts)
 		v1.GET("/providers", routes.GetProviders)
+		v1.GET("/tags", routes.GetTags)
 
 		//Methods singular GET
 		v1.GET("/customers/:rut", routes.GetCustomer)
 		v1.GET("/providers/:rut", routes.GetProvider)
 		v1.GET("/products/:id", routes.GetProduct)
-		v1.GET("/accounts/:mail", routes.GetAccount)
 		v1.GET("/tags/:id", routes.GetTag)
 		v1.GET("/sale_detail/:sale_id/:product_id", routes.GetSaleDetail)
-		v1.GET("/purchase_detail/:purchase_id/:product_id", routes.GetPurchaseDetail)
-		v1.GET("/sales/:cus_id/:user_id", routes.GetSale)
-		v1.GET("/purchases/:prov_id", routes.PostPurchase)
-
-		//Methods POST
-		v1.POST("/customers", routes.PostCustomer)
-		v1.POST("/providers", routes.PostProvider)
-		v1.POST("/products", routes.PostProduct)
-		v1.POST("/accounts", routes.PostAccount)
-		v1.POST("/tags", routes.PostTag)
-		v1.POST("/sale_detail", routes.PostSaleDetail)
-		v1.POST("/purchase_detail", routes.PostPurchaseDetail)
-		v1.POST("/sales", routes.PostSale)
-		v1.POST("/purchases", routes.PostPurchase)
-		v1.POST("tags_customer", routes.PostTagCustomer)
-
-		// *** Admin and manager ***
-		// Stats
-		v1.POST("/productsrank-k/:k", routes.GetRankProductK)
-		v1.POST("/productsrank-cs/:k/:category", routes.GetRankProductCategoryS)
-		v1.POST("/productsrank-cp/:k/:category", routes.GetRankProductCategoryP)
-		v1.POST("/productsrank-b/:k/:brand", routes.GetRankProductBrand)
-		v1.POST("/productsrank-pp/:id_product", routes.GetRankProductPP)
-		v1.POST("/productsrank-r/:k", routes.GetRankProfitability)
-
-		v1.POST("/customersrank-k/:k", routes.GetRankCustomerK)
-		v1.POST("/customersrank-p/:k/:l", routes.GetRankCustomerKL)
-		v1.POST("/customersrank-v/:k", routes.GetRankCustomerVariety)
-		v1.POST("/customersrank-f/:k", routes.GetRankFrequency)
-
-		v1.POST("/purchasesrank-k/:k", routes.GetRankPurchasesK)
-		v1.POST("/purchasesrank-cp/:k/:category", routes.GetRankPurchasesCP)
-		v1.POST("/purchasesrank-p/:k", routes.GetRankPurchasesProduct)
-
-		v1.POST("/providersrank-k/:k", routes.GetRankProviderK)
-		v1.POST("/providersrank-v/:k", routes.GetRankProviderVariety)
-		v1.POST("/providersrank-pp/:k/:id_provider", routes.GetRankProviderPP)
-
-		v1.POST("/salesrank-k/:k", routes.GetRankSalesK)
-		v1.POST("/salesrank-c/:k/:category", routes.GetRankSalesCategory)
-		v1.POST("/salesrank-p/:k", routes.GetRankSalesProduct)
-		v1.POST("/salesrank-r/:k", routes.GetRankSalesArea)
-
-		// Record
-		v1.POST("/productsrec/:id", routes.GetSalesProductIDRec)
+		v1.GET("/sales", routes.GetSaleSummaries)
+		v1.GET("/sales/:id", routes.GetSale)
+		v1.GET("/stock", routes.GetStocks)
+		v1.GET("/stock/:product_id", routes.GetStock)
+		v1.GET("/stock/:product_id/movements", routes.GetStockMovements)
+		v1.GET("/warehouses", routes.GetWarehouses)
+		v1.GET("/warehouses/:id", routes.GetWarehouse)
+		v1.GET("/warehouses/:id/stock", routes.GetWarehouseStocks)
+		v1.GET("/backorders", routes.GetBackOrders)
+		v1.GET("/customers/:rut/backorders", routes.GetCustomerBackOrders)
 
-		v1.POST("/customersrec-p/:id_customer", routes.GetProductTotal)
-		v1.POST("/customersrec-c/:id_customer", routes.GetTotalCash)
+		//Methods PUT and PATCH
+		v1.PUT("/customers/:rut", routes.Audit("customer"), routes.PutCustomer)
+		v1.PATCH("/customers/:rut", routes.Audit("customer"), routes.PatchCustomer)
+		v1.PUT("/me/password", routes.Audit("account"), routes.PutMyPassword)
 
-		v1.POST("/purchasesrec-p/:id_product", routes.GetPurchasesProduct)
+		//Second factor
+		v1.POST("/me/totp", routes.Audit("account"), routes.PostMyTOTP)
+		v1.POST("/me/totp/confirm", routes.Audit("account"), routes.PostMyTOTPConfirm)
+		v1.DELETE("/me/totp", routes.Audit("account"), routes.DeleteMyTOTP)
 
-		v1.POST("/salesrec-p/:id_product", routes.GetSalesProduct)
-		v1.POST("/sales-total", routes.GetSales)
+		//Methods POST
+		v1.POST("/customers", routes.Audit("customer"), routes.PostCustomer)
+		v1.POST("/tags", routes.Audit("tag"), routes.PostTag)
+		v1.POST("tags_customer", routes.Audit("tag_customer"), routes.PostTagCustomer)
 
 		// *** Seller ***
 		// Stats
@@ -142,5 +128,139 @@ func main() {
 		// *** Dashboard ***
 		v1.GET("/dashboard-info/:role/:id_seller", routes.GetInformationDashboard)
 	}
+
+	// Sales, also written by API keys
+	sales := api.Group("")
+	sales.Use(auth.RequireScope(model.ScopeWrite,
+		model.ADMIN, model.MANAGER, model.SELLER))
+	{
+		sales.POST("/sale_detail", routes.Audit("sale_detail"), routes.PostSaleDetail)
+		sales.POST("/sales", routes.Audit("sale"), routes.PostSale)
+	}
+
+	// *** Admin and manager ***
+	manager := api.Group("")
+	manager.Use(auth.RequireRole(model.ADMIN, model.MANAGER))
+	{
+		manager.GET("/purchase_detail/:purchase_id/:product_id", routes.GetPurchaseDetail)
+		manager.GET("/purchases", routes.GetPurchases)
+		manager.GET("/purchases/:id", routes.GetPurchase)
+
+		manager.POST("/providers", routes.Audit("provider"), routes.PostProvider)
+		manager.PUT("/providers/:rut", routes.Audit("provider"), routes.PutProvider)
+		manager.PATCH("/providers/:rut", routes.Audit("provider"), routes.PatchProvider)
+		manager.DELETE("/providers/:rut", routes.Audit("provider"), routes.DeleteProvider)
+		manager.POST("/providers/:rut/restore", routes.Audit("provider"), routes.RestoreProvider)
+
+		manager.DELETE("/customers/:rut", routes.Audit("customer"), routes.DeleteCustomer)
+		manager.POST("/customers/:rut/restore", routes.Audit("customer"), routes.RestoreCustomer)
+
+		manager.POST("/products", routes.Audit("product"), routes.PostProduct)
+		manager.PUT("/products/:id", routes.Audit("product"), routes.PutProduct)
+		manager.PATCH("/products/:id", routes.Audit("product"), routes.PatchProduct)
+		manager.DELETE("/products/:id", routes.Audit("product"), routes.DeleteProduct)
+		manager.POST("/products/:id/restore", routes.Audit("product"), routes.RestoreProduct)
+
+		manager.PUT("/tags/:id", routes.Audit("tag"), routes.PutTag)
+		manager.PATCH("/tags/:id", routes.Audit("tag"), routes.PatchTag)
+		manager.DELETE("/tags/:id", routes.Audit("tag"), routes.DeleteTag)
+		manager.POST("/tags/:id/restore", routes.Audit("tag"), routes.RestoreTag)
+
+		manager.POST("/warehouses", routes.Audit("warehouse"), routes.PostWarehouse)
+		manager.PUT("/warehouses/:id", routes.Audit("warehouse"), routes.PutWarehouse)
+		manager.PATCH("/warehouses/:id", routes.Audit("warehouse"), routes.PatchWarehouse)
+		manager.DELETE("/warehouses/:id", routes.Audit("warehouse"), routes.DeleteWarehouse)
+		manager.POST("/warehouses/:id/restore", routes.Audit("warehouse"), routes.RestoreWarehouse)
+
+		manager.GET("/transfers", routes.GetTransfers)
+		manager.GET("/transfers/:id", routes.GetTransfer)
+		manager.POST("/transfers", routes.Audit("transfer"), routes.PostTransfer)
+
+		manager.GET("/reorder", routes.GetReorders)
+		manager.POST("/stock/movements", routes.Audit("stock_movement"), routes.PostStockMovement)
+
+		manager.GET("/stock-counts", routes.GetStockCounts)
+		manager.GET("/stock-counts/:id", routes.GetStockCount)
+		manager.GET("/stock-counts/:id/variance", routes.GetStockCountReport)
+		manager.POST("/stock-counts", routes.Audit("stock_count"), routes.PostStockCount)
+		manager.POST("/stock-counts/:id/lines", routes.Audit("stock_count"), routes.PostStockCountLines)
+		manager.POST("/stock-counts/:id/close", routes.Audit("stock_count"), routes.CloseStockCount)
+	}
+
+	// Purchases, also written by API keys
+	purchases := api.Group("")
+	purchases.Use(auth.RequireScope(model.ScopeWrite, model.ADMIN, model.MANAGER))
+	{
+		purchases.POST("/purchase_detail", routes.Audit("purchase_detail"), routes.PostPurchaseDetail)
+		purchases.POST("/purchases", routes.Audit("purchase"), routes.PostPurchase)
+	}
+
+	// Company-wide stats, also read by API keys
+	stats := api.Group("")
+	stats.Use(auth.RequireScope(model.ScopeStats, model.ADMIN, model.MANAGER))
+	{
+		// Stats
+		stats.POST("/productsrank-k/:k", routes.GetRankProductK)
+		stats.POST("/productsrank-cs/:k/:category", routes.GetRankProductCategoryS)
+		stats.POST("/productsrank-cp/:k/:category", routes.GetRankProductCategoryP)
+		stats.POST("/productsrank-b/:k/:brand", routes.GetRankProductBrand)
+		stats.POST("/productsrank-pp/:id_product", routes.GetRankProductPP)
+		stats.POST("/productsrank-r/:k", routes.GetRankProfitability)
+
+		stats.POST("/customersrank-k/:k", routes.GetRankCustomerK)
+		stats.POST("/customersrank-p/:k/:l", routes.GetRankCustomerKL)
+		stats.POST("/customersrank-v/:k", routes.GetRankCustomerVariety)
+		stats.POST("/customersrank-f/:k", routes.GetRankFrequency)
+
+		stats.POST("/purchasesrank-k/:k", routes.GetRankPurchasesK)
+		stats.POST("/purchasesrank-cp/:k/:category", routes.GetRankPurchasesCP)
+		stats.POST("/purchasesrank-p/:k", routes.GetRankPurchasesProduct)
+
+		stats.POST("/providersrank-k/:k", routes.GetRankProviderK)
+		stats.POST("/providersrank-v/:k", routes.GetRankProviderVariety)
+		stats.POST("/providersrank-pp/:k/:id_provider", routes.GetRankProviderPP)
+
+		stats.POST("/salesrank-k/:k", routes.GetRankSalesK)
+		stats.POST("/salesrank-c/:k/:category", routes.GetRankSalesCategory)
+		stats.POST("/salesrank-p/:k", routes.GetRankSalesProduct)
+		stats.POST("/salesrank-r/:k", routes.GetRankSalesArea)
+
+		// Record
+		stats.POST("/productsrec/:id", routes.GetSalesProductIDRec)
+
+		stats.POST("/customersrec-p/:id_customer", routes.GetProductTotal)
+		stats.POST("/customersrec-c/:id_customer", routes.GetTotalCash)
+
+		stats.POST("/purchasesrec-p/:id_product", routes.GetPurchasesProduct)
+
+		stats.POST("/salesrec-p/:id_product", routes.GetSalesProduct)
+		stats.POST("/sales-total", routes.GetSales)
+	}
+
+	// *** Admin ***
+	admin := api.Group("")
+	admin.Use(auth.RequireRole(model.ADMIN))
+	{
+		admin.GET("/accounts", routes.GetAccounts)
+		admin.GET("/accounts/:mail", routes.GetAccount)
+		admin.POST("/accounts", routes.Audit("account"), routes.PostAccount)
+		admin.PUT("/accounts/:mail", routes.Audit("account"), routes.PutAccount)
+		admin.PUT("/accounts/:mail/role", routes.Audit("account"), routes.PutAccountRole)
+		admin.PUT("/accounts/:mail/password", routes.Audit("account"), routes.PutAccountPassword)
+		admin.PUT("/accounts/:mail/deactivate", routes.Audit("account"), routes.DeactivateAccount)
+		admin.PUT("/accounts/:mail/reactivate", routes.Audit("account"), routes.ReactivateAccount)
+		admin.DELETE("/accounts/:mail", routes.Audit("account"), routes.DeleteAccount)
+		admin.DELETE("/accounts/:mail/totp", routes.Audit("account"), routes.DeleteAccountTOTP)
+
+		admin.GET("/lockouts", routes.GetLockouts)
+		admin.DELETE("/lockouts/:key", routes.Audit("lockout"), routes.DeleteLockout)
+		admin.GET("/login-history", routes.GetLoginHistory)
+
+		admin.GET("/api-keys", routes.GetAPIKeys)
+		admin.POST("/api-keys", routes.Audit("api_key"), routes.PostAPIKey)
+		admin.DELETE("/api-keys/:id", routes.Audit("api_key"), routes.DeleteAPIKey)
+
+		admin.GET("/audit", routes.GetAuditLogs)
+	}
 	r.Run(":" + port)
 }
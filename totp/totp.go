@@ -0,0 +1,82 @@
+//Package totp generates and checks time-based one-time
+//passwords (RFC 6238) of authenticator apps
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//Parameters of the codes, they're the defaults of authenticator apps
+var (
+	issuer = "Coimco"
+	period = int64(30)
+	digits = 6
+	//Accepted steps before and after the current one
+	skew = int64(1)
+)
+
+//Secrets are encoded in base32 without padding
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+//NewSecret returns a random secret of 160 bits
+func NewSecret() (string, error) {
+	bytes := make([]byte, 20)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(bytes), nil
+}
+
+//URI returns the provisioning URI shown as QR code
+func URI(mail, secret string) string {
+	label := url.PathEscape(issuer + ":" + mail)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(period))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+//Code returns the code of a time step
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod), nil
+}
+
+//Check returns the time step of a valid code
+func Check(secret, code string, now time.Time) (int64, bool) {
+	current := now.Unix() / period
+	for step := current - skew; step <= current+skew; step++ {
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
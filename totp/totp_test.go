@@ -0,0 +1,109 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+//Secret of the RFC 6238 test vectors, "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	//RFC 6238 SHA1 vectors, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, test := range tests {
+		code, err := Code(rfcSecret, test.unix/period)
+		if err != nil || code != test.code {
+			t.Errorf("Code(%d) = %q, %v, want %q", test.unix, code, err, test.code)
+		}
+	}
+	lower, err := Code("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", 59/period)
+	if err != nil || lower != "287082" {
+		t.Errorf("Code with lowercase secret = %q, %v, want %q", lower, err, "287082")
+	}
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code with a malformed secret must fail")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / period
+	tests := []struct {
+		name   string
+		secret string
+		step   int64
+		ok     bool
+	}{
+		{"current step", rfcSecret, current, true},
+		{"previous step", rfcSecret, current - 1, true},
+		{"next step", rfcSecret, current + 1, true},
+		{"too old", rfcSecret, current - 2, false},
+		{"too new", rfcSecret, current + 2, false},
+	}
+	for _, test := range tests {
+		code, _ := Code(rfcSecret, test.step)
+		step, ok := Check(test.secret, code, now)
+		if ok != test.ok || (ok && step != test.step) {
+			t.Errorf("%s: Check = %d, %v, want %d, %v",
+				test.name, step, ok, test.step, test.ok)
+		}
+	}
+	if _, ok := Check(rfcSecret, "000000", now); ok {
+		t.Error("Check must reject a wrong code")
+	}
+	if _, ok := Check("not base32!", "050471", now); ok {
+		t.Error("Check must reject a malformed secret")
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Errorf("NewSecret() = %q, it must be 20 bytes in base32", secret)
+	}
+	other, _ := NewSecret()
+	if other == secret {
+		t.Error("NewSecret must return random secrets")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("ana@coimco.cl", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" {
+		t.Errorf("URI = %s, want an otpauth://totp URI", uri)
+	}
+	if uri.Path != "/Coimco:ana@coimco.cl" {
+		t.Errorf("URI label = %q, want %q", uri.Path, "/Coimco:ana@coimco.cl")
+	}
+	query := uri.Query()
+	want := map[string]string{
+		"secret": rfcSecret,
+		"issuer": "Coimco",
+		"digits": "6",
+		"period": "30",
+	}
+	for key, value := range want {
+		if query.Get(key) != value {
+			t.Errorf("URI %s = %q, want %q", key, query.Get(key), value)
+		}
+	}
+}
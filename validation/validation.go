@@ -0,0 +1,197 @@
+//Package validation validates the request structs by their 'binding'
+//tags, it's the validator used by gin to bind requests.
+//Besides the rules of validator.v8 it has:
+//
+//	rut          the field is a valid Chilean RUT
+//	unique=name  the items of a slice don't repeat the field with json name
+//	oneof=a b    the field is one of the words
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fabulias/coimco_backend/rut"
+	"gopkg.in/go-playground/validator.v8"
+)
+
+var validate = newValidate()
+
+//Messages of the rules, %s is the param of the rule
+var messages = map[string]string{
+	"required": "is required",
+	"exists":   "is required",
+	"email":    "must be a valid email",
+	"rut":      "must be a valid RUT",
+	"gt":       "must be greater than %s",
+	"gte":      "must be at least %s",
+	"min":      "must be at least %s",
+	"max":      "must be at most %s",
+	"gtfield":  "must be after %s",
+	"gtefield": "must not be before %s",
+	"nefield":  "must be different from %s",
+	"unique":   "must not repeat %s",
+	"oneof":    "must be one of: %s",
+}
+
+//FieldError represents a rule that a field doesn't meet,
+//Field is the path of json names, e.g. "details[0].quantity"
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+//Errors represents the field errors of a struct
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	fields := make([]string, len(e))
+	for i, err := range e {
+		fields[i] = err.Field + " " + err.Message
+	}
+	return strings.Join(fields, ", ")
+}
+
+//StructValidator is the gin binding validator
+type StructValidator struct{}
+
+//ValidateStruct validates the struct pointed by obj
+func (StructValidator) ValidateStruct(obj interface{}) error {
+	if errs := Struct(obj); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+//Struct validates a struct or a pointer to a struct,
+//it return nil if it's valid
+func Struct(obj interface{}) Errors {
+	value := reflect.ValueOf(obj)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	invalid, ok := validate.Struct(obj).(validator.ValidationErrors)
+	if !ok {
+		return nil
+	}
+	errs := make(Errors, 0, len(invalid))
+	for _, err := range invalid {
+		message, ok := messages[err.Tag]
+		if !ok {
+			message = "isn't valid"
+		}
+		if strings.Contains(message, "%s") {
+			message = fmt.Sprintf(message, fieldName(err.Param))
+		}
+		errs = append(errs, FieldError{
+			Field:   fieldPath(err.NameNamespace),
+			Rule:    err.Tag,
+			Message: message,
+		})
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+func newValidate() *validator.Validate {
+	v := validator.New(&validator.Config{TagName: "binding", FieldNameTag: "json"})
+	v.RegisterValidation("rut", isRut)
+	v.RegisterValidation("unique", isUnique)
+	v.RegisterValidation("oneof", isOneOf)
+	return v
+}
+
+//fieldPath return the json path of a field namespace, without the
+//top struct and embedded structs, which names start with uppercase
+func fieldPath(namespace string) string {
+	var path []string
+	for _, name := range strings.Split(namespace, ".")[1:] {
+		if name != "" && strings.ToUpper(name[:1]) == name[:1] {
+			continue
+		}
+		path = append(path, name)
+	}
+	return strings.Join(path, ".")
+}
+
+//fieldName return a struct field name as json name,
+//e.g. ShipTime is ship_time and FromID is from_id
+func fieldName(name string) string {
+	var out []rune
+	lower := false
+	for _, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if lower {
+				out = append(out, '_')
+			}
+			r += 'a' - 'A'
+			lower = false
+		} else {
+			lower = true
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+func isRut(v *validator.Validate, topStruct reflect.Value, currentStruct reflect.Value,
+	field reflect.Value, fieldType reflect.Type, fieldKind reflect.Kind, param string) bool {
+	return fieldKind == reflect.String && rut.Valid(field.String())
+}
+
+func isUnique(v *validator.Validate, topStruct reflect.Value, currentStruct reflect.Value,
+	field reflect.Value, fieldType reflect.Type, fieldKind reflect.Kind, param string) bool {
+	if fieldKind != reflect.Slice {
+		return false
+	}
+	seen := map[interface{}]bool{}
+	for i := 0; i < field.Len(); i++ {
+		item := reflect.Indirect(field.Index(i))
+		value, ok := jsonField(item, param)
+		if !ok {
+			return false
+		}
+		if seen[value] {
+			return false
+		}
+		seen[value] = true
+	}
+	return true
+}
+
+func isOneOf(v *validator.Validate, topStruct reflect.Value, currentStruct reflect.Value,
+	field reflect.Value, fieldType reflect.Type, fieldKind reflect.Kind, param string) bool {
+	for _, word := range strings.Fields(param) {
+		if fieldKind == reflect.String && field.String() == word {
+			return true
+		}
+	}
+	return false
+}
+
+//jsonField return the value of the struct field with the json name,
+//fields of embedded structs included
+func jsonField(item reflect.Value, name string) (interface{}, bool) {
+	if item.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for i := 0; i < item.NumField(); i++ {
+		field := item.Type().Field(i)
+		if field.Anonymous {
+			if value, ok := jsonField(item.Field(i), name); ok {
+				return value, true
+			}
+			continue
+		}
+		if strings.SplitN(field.Tag.Get("json"), ",", 2)[0] == name {
+			return item.Field(i).Interface(), true
+		}
+	}
+	return nil, false
+}
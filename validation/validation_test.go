@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+//TestBase is embedded, its fields are at the top of the json path
+type TestBase struct {
+	Rut string `json:"rut" binding:"required,rut"`
+}
+
+type testLine struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"gt=0"`
+}
+
+type testDoc struct {
+	TestBase
+	Mail    string     `json:"mail" binding:"omitempty,email"`
+	Status  string     `json:"status" binding:"omitempty,oneof=open closed"`
+	FromID  uint       `json:"from_id" binding:"required"`
+	ToID    uint       `json:"to_id" binding:"required,nefield=FromID"`
+	Details []testLine `json:"details" binding:"required,min=1,unique=product_id,dive"`
+}
+
+//validDoc returns a document without errors
+func validDoc() testDoc {
+	return testDoc{
+		TestBase: TestBase{Rut: "12.345.678-5"},
+		Mail:     "ana@coimco.cl",
+		Status:   "open",
+		FromID:   1,
+		ToID:     2,
+		Details:  []testLine{{1, 5}, {2, 1}},
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(doc *testDoc)
+		errs   Errors
+	}{
+		{"valid", func(doc *testDoc) {}, nil},
+		{"K check digit", func(doc *testDoc) { doc.Rut = "10000013-k" }, nil},
+		{"missing rut", func(doc *testDoc) { doc.Rut = "" },
+			Errors{{"rut", "required", "is required"}}},
+		{"wrong check digit", func(doc *testDoc) { doc.Rut = "12.345.678-4" },
+			Errors{{"rut", "rut", "must be a valid RUT"}}},
+		{"email", func(doc *testDoc) { doc.Mail = "ana" },
+			Errors{{"mail", "email", "must be a valid email"}}},
+		{"oneof", func(doc *testDoc) { doc.Status = "draft" },
+			Errors{{"status", "oneof", "must be one of: open closed"}}},
+		{"nefield", func(doc *testDoc) { doc.ToID = doc.FromID },
+			Errors{{"to_id", "nefield", "must be different from from_id"}}},
+		{"missing details", func(doc *testDoc) { doc.Details = nil },
+			Errors{{"details", "required", "is required"}}},
+		{"empty details", func(doc *testDoc) { doc.Details = []testLine{} },
+			Errors{{"details", "min", "must be at least 1"}}},
+		{"repeated product", func(doc *testDoc) { doc.Details[1].ProductID = 1 },
+			Errors{{"details", "unique", "must not repeat product_id"}}},
+		{"line item", func(doc *testDoc) { doc.Details[1].Quantity = 0 },
+			Errors{{"details[1].quantity", "gt", "must be greater than 0"}}},
+		{"sorted by field", func(doc *testDoc) {
+			doc.Rut = "1-1"
+			doc.Details[0].ProductID = 0
+			doc.Mail = "ana"
+		}, Errors{
+			{"details[0].product_id", "required", "is required"},
+			{"mail", "email", "must be a valid email"},
+			{"rut", "rut", "must be a valid RUT"},
+		}},
+	}
+	for _, test := range tests {
+		doc := validDoc()
+		test.change(&doc)
+		if errs := Struct(&doc); !reflect.DeepEqual(errs, test.errs) {
+			t.Errorf("%s: Struct = %v, want %v", test.name, errs, test.errs)
+		}
+	}
+}
+
+func TestStructNotStruct(t *testing.T) {
+	values := []interface{}{nil, 1, "text", []testLine{{0, 0}}}
+	for _, value := range values {
+		if errs := Struct(value); errs != nil {
+			t.Errorf("Struct(%v) = %v, want nil", value, errs)
+		}
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Start", "start"},
+		{"ShipTime", "ship_time"},
+		{"FromID", "from_id"},
+		{"ProductID", "product_id"},
+		{"product_id", "product_id"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if name := fieldName(test.name); name != test.want {
+			t.Errorf("fieldName(%q) = %q, want %q", test.name, name, test.want)
+		}
+	}
+}
+
+func TestErrorsError(t *testing.T) {
+	errs := Errors{
+		{"rut", "rut", "must be a valid RUT"},
+		{"details", "min", "must be at least 1"},
+	}
+	want := "rut must be a valid RUT, details must be at least 1"
+	if errs.Error() != want {
+		t.Errorf("Error() = %q, want %q", errs.Error(), want)
+	}
+}